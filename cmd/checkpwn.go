@@ -7,7 +7,7 @@ import (
 	"os"
 	"strings"
 
-	"pwdforge/internal/pwnchecker"
+	"pwdforge/pkg/pwdforge"
 
 	"github.com/spf13/cobra"
 )
@@ -15,11 +15,15 @@ import (
 var checkpwnCmd = &cobra.Command{
 	Use:   "checkpwn",
 	Short: "Check if a password has been exposed in data breaches",
-	Long:  "Uses the HaveIBeenPwned API to securely check if a password has been pwned.",
+	Long: `Uses the HaveIBeenPwned API to securely check if a password has been pwned.
+Only the first five characters of the password's SHA-1 hash are sent.
+--api-url points the check at a mirror, such as one run with serve-range.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		password, _ := cmd.Flags().GetString("password")
 		inputFile, _ := cmd.Flags().GetString("input")
 		format, _ := cmd.Flags().GetString("format")
+		apiURL, _ := cmd.Flags().GetString("api-url")
+		checker := pwdforge.BreachChecker{APIURL: apiURL}
 		if strings.TrimSpace(password) == "" && strings.TrimSpace(inputFile) == "" {
 			fmt.Fprintln(os.Stderr, "Error: Either --password or --input must be provided.")
 			os.Exit(1)
@@ -42,7 +46,7 @@ var checkpwnCmd = &cobra.Command{
 				if pw == "" {
 					continue
 				}
-				exposed, count, err := pwnchecker.CheckPasswordPwned(pw)
+				exposed, count, err := checker.Check(cmd.Context(), pw)
 				result := map[string]interface{}{
 					"password": pw,
 					"exposed":  exposed,
@@ -59,7 +63,7 @@ var checkpwnCmd = &cobra.Command{
 				os.Exit(1)
 			}
 		} else {
-			exposed, count, err := pwnchecker.CheckPasswordPwned(password)
+			exposed, count, err := checker.Check(cmd.Context(), password)
 			result := map[string]interface{}{
 				"password": password,
 				"exposed":  exposed,
@@ -102,6 +106,6 @@ func init() {
 	// Removed MarkFlagRequired("password")
 	checkpwnCmd.Flags().String("format", "plain", "Output format: plain, json, table")
 	checkpwnCmd.Flags().String("input", "", "Read passwords to check from a file (one per line)")
-	checkpwnCmd.Flags().String("config", "", "Path to config file for default options")
+	checkpwnCmd.Flags().String("api-url", pwdforge.DefaultBreachAPIURL, "Base URL of the range API, e.g. a serve-range mirror")
 	RootCmd.AddCommand(checkpwnCmd)
 }
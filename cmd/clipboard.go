@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	clipboard "pwdforge/pkg"
+
+	"github.com/spf13/cobra"
+)
+
+// Default time after which a copied secret is removed from the clipboard.
+const defaultClipboardTimeout = 45 * time.Second
+
+// clipboardClearCmd is the detached helper started by clipboard.ScheduleClear.
+var clipboardClearCmd = &cobra.Command{
+	Use:    clipboard.HelperCommand,
+	Short:  "Clear the clipboard if it still holds a copied secret",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		backend, _ := cmd.Flags().GetString("backend")
+		after, _ := cmd.Flags().GetDuration("after")
+		sum := os.Getenv(clipboard.SumEnv)
+		if sum == "" {
+			os.Exit(1)
+		}
+		time.Sleep(after)
+		b, err := clipboard.Lookup(backend)
+		if err != nil {
+			os.Exit(1)
+		}
+		if _, err := clipboard.ClearIfUnchanged(b, sum); err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+// copySecret copies text to the clipboard and schedules it to be cleared
+// after timeout (0 keeps it). Status messages go to stderr so they never mix
+// with machine-readable output.
+func copySecret(text string, timeout time.Duration) error {
+	b, err := clipboard.Detect()
+	if err != nil {
+		return err
+	}
+	if err := b.Copy(text); err != nil {
+		return fmt.Errorf("copying to clipboard via %s: %w", b.Name, err)
+	}
+	if timeout <= 0 {
+		fmt.Fprintf(os.Stderr, "[+] Copied to clipboard (%s)\n", b.Name)
+		return nil
+	}
+	if err := clipboard.ScheduleClear(b, text, timeout); err != nil {
+		fmt.Fprintf(os.Stderr, "[!] Copied to clipboard (%s), but it will not be cleared automatically: %v\n", b.Name, err)
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "[+] Copied to clipboard (%s), clearing in %s\n", b.Name, timeout)
+	return nil
+}
+
+func init() {
+	clipboardClearCmd.Flags().String("backend", "", "Clipboard backend to clear")
+	clipboardClearCmd.Flags().Duration("after", defaultClipboardTimeout, "Delay before clearing")
+	RootCmd.AddCommand(clipboardClearCmd)
+}
@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"pwdforge/internal/config"
+	"pwdforge/pkg/pwdforge"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// GenerateOptions are the generation settings of one configuration layer,
+// such as a config file or an --input entry: pwdforge.Options with every
+// field optional. Unset (nil) fields keep the value of the layers below,
+// so a layer can turn an option off with false.
+type GenerateOptions struct {
+	Length          *int              `yaml:"length,omitempty" json:"length,omitempty"`
+	Count           *int              `yaml:"count,omitempty" json:"count,omitempty"`
+	IncludeUpper    *bool             `yaml:"include_upper,omitempty" json:"include_upper,omitempty"`
+	IncludeLower    *bool             `yaml:"include_lower,omitempty" json:"include_lower,omitempty"`
+	IncludeDigits   *bool             `yaml:"include_digits,omitempty" json:"include_digits,omitempty"`
+	IncludeSpecials *bool             `yaml:"include_specials,omitempty" json:"include_specials,omitempty"`
+	ExcludeSimilar  *bool             `yaml:"exclude_similar,omitempty" json:"exclude_similar,omitempty"`
+	ExcludeProfiles []string          `yaml:"exclude_profiles,omitempty" json:"exclude_profiles,omitempty"`
+	CustomCharset   *string           `yaml:"custom_charset,omitempty" json:"custom_charset,omitempty"`
+	ExcludeChars    *string           `yaml:"exclude_chars,omitempty" json:"exclude_chars,omitempty"`
+	EnforceAll      *bool             `yaml:"enforce_all,omitempty" json:"enforce_all,omitempty"`
+	Passphrase      *bool             `yaml:"passphrase,omitempty" json:"passphrase,omitempty"`
+	Pronounceable   *bool             `yaml:"pronounceable,omitempty" json:"pronounceable,omitempty"`
+	PIN             *bool             `yaml:"pin,omitempty" json:"pin,omitempty"`
+	PINAllow        []string          `yaml:"pin_allow,omitempty" json:"pin_allow,omitempty"`
+	Pattern         *string           `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	Target          *string           `yaml:"target,omitempty" json:"target,omitempty"`
+	Type            *string           `yaml:"type,omitempty" json:"type,omitempty"`
+	Bytes           *int              `yaml:"bytes,omitempty" json:"bytes,omitempty"`
+	KeyPrefix       *string           `yaml:"key_prefix,omitempty" json:"key_prefix,omitempty"`
+	PatternClasses  map[string]string `yaml:"pattern_classes,omitempty" json:"pattern_classes,omitempty"`
+	WordCount       *int              `yaml:"word_count,omitempty" json:"word_count,omitempty"`
+	// Policy and Context replace those of lower layers as a whole.
+	Policy  *pwdforge.Policy  `yaml:"policy,omitempty" json:"policy,omitempty"`
+	Context map[string]string `yaml:"context,omitempty" json:"context,omitempty"`
+}
+
+// Options returns the library options; unset fields are zero, which the
+// library reads as its defaults.
+func (o GenerateOptions) Options() pwdforge.Options {
+	return pwdforge.Options{
+		Length:          value(o.Length),
+		Count:           value(o.Count),
+		IncludeUpper:    value(o.IncludeUpper),
+		IncludeLower:    value(o.IncludeLower),
+		IncludeDigits:   value(o.IncludeDigits),
+		IncludeSpecials: value(o.IncludeSpecials),
+		ExcludeSimilar:  value(o.ExcludeSimilar),
+		ExcludeProfiles: o.ExcludeProfiles,
+		CustomCharset:   value(o.CustomCharset),
+		ExcludeChars:    value(o.ExcludeChars),
+		EnforceAll:      value(o.EnforceAll),
+		Passphrase:      value(o.Passphrase),
+		Pronounceable:   value(o.Pronounceable),
+		PIN:             value(o.PIN),
+		PINAllow:        o.PINAllow,
+		Pattern:         value(o.Pattern),
+		Target:          value(o.Target),
+		Type:            value(o.Type),
+		Bytes:           value(o.Bytes),
+		KeyPrefix:       value(o.KeyPrefix),
+		PatternClasses:  o.PatternClasses,
+		WordCount:       value(o.WordCount),
+		Policy:          o.Policy,
+		Context:         o.Context,
+	}
+}
+
+// value returns *p, or the zero value when p is nil.
+func value[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
+
+// GenerateConfig is the schema of config files: generation options plus
+// settings of the whole generate run, which --input entries cannot set.
+type GenerateConfig struct {
+	GenerateOptions `yaml:",inline"`
+	Hash            *string `yaml:"hash,omitempty" json:"hash,omitempty"`
+	HashOnly        *bool   `yaml:"hash_only,omitempty" json:"hash_only,omitempty"`
+	// ClipboardTimeout is a Go duration such as "30s"; "0" keeps the clipboard.
+	ClipboardTimeout *string `yaml:"clipboard_timeout,omitempty" json:"clipboard_timeout,omitempty"`
+	// BannedWordFiles may not appear in passwords.
+	BannedWordFiles []string `yaml:"banned_word_files,omitempty" json:"banned_word_files,omitempty"`
+	// Workers is the generate --workers default.
+	Workers *int `yaml:"workers,omitempty" json:"workers,omitempty"`
+}
+
+// generateDefaults is the built-in defaults layer: the defaults of the
+// generate flags, read before the flags are parsed.
+var generateDefaults GenerateConfig
+
+// generateConfigLayers returns the layers below an --input entry and the
+// flags: the built-in defaults, the config files and profiles loaded for
+// the command, and the PWDFORGE_* environment variables. It also returns
+// the variable each environment key was read from.
+func generateConfigLayers() ([]config.Layer, map[string]string, error) {
+	layers := []config.Layer{{Name: config.Default, Values: &generateDefaults}}
+	for _, l := range activeConfig {
+		layers = append(layers, config.Layer{Name: l.name, Values: &l.GenerateConfig})
+	}
+	var env GenerateConfig
+	vars, err := config.FromEnv(&env, os.LookupEnv)
+	if err != nil {
+		return nil, nil, err
+	}
+	layers = append(layers, config.Layer{Name: envLayer, Values: &env})
+	return layers, vars, nil
+}
+
+// generateKeyFlags maps the generate flags that are config keys to the
+// fields of c they set.
+func generateKeyFlags(c *GenerateConfig) map[string]any {
+	return map[string]any{
+		"length":            &c.Length,
+		"count":             &c.Count,
+		"uppercase":         &c.IncludeUpper,
+		"lowercase":         &c.IncludeLower,
+		"digits":            &c.IncludeDigits,
+		"specials":          &c.IncludeSpecials,
+		"exclude-similar":   &c.ExcludeSimilar,
+		"exclude-profile":   &c.ExcludeProfiles,
+		"custom-charset":    &c.CustomCharset,
+		"exclude-chars":     &c.ExcludeChars,
+		"enforce-all":       &c.EnforceAll,
+		"passphrase":        &c.Passphrase,
+		"pronounceable":     &c.Pronounceable,
+		"pin":               &c.PIN,
+		"pin-allow":         &c.PINAllow,
+		"pattern":           &c.Pattern,
+		"target":            &c.Target,
+		"type":              &c.Type,
+		"bytes":             &c.Bytes,
+		"key-prefix":        &c.KeyPrefix,
+		"word-count":        &c.WordCount,
+		"context":           &c.Context,
+		"hash":              &c.Hash,
+		"hash-only":         &c.HashOnly,
+		"clipboard-timeout": &c.ClipboardTimeout,
+		"banned-words":      &c.BannedWordFiles,
+		"workers":           &c.Workers,
+	}
+}
+
+// isGenerateKeyFlag reports whether the generate flag name is a config key.
+func isGenerateKeyFlag(name string) bool {
+	_, ok := generateKeyFlags(&GenerateConfig{})[name]
+	return ok
+}
+
+// generateFlagLayer returns the generate flags in f that map to config
+// keys: those given on the command line, or with defaults all of them at
+// their default values. --length has no default layer value, since the
+// library's default depends on the mode (6 digits for PINs).
+func generateFlagLayer(f *pflag.FlagSet, defaults bool) GenerateConfig {
+	var c GenerateConfig
+	for name, field := range generateKeyFlags(&c) {
+		if (defaults && name == "length") || (!defaults && !f.Changed(name)) {
+			continue
+		}
+		switch field := field.(type) {
+		case **int:
+			v, _ := f.GetInt(name)
+			*field = &v
+		case **bool:
+			v, _ := f.GetBool(name)
+			*field = &v
+		case **string:
+			// Also renders durations such as --clipboard-timeout.
+			v := f.Lookup(name).Value.String()
+			*field = &v
+		case *[]string:
+			if v, _ := f.GetStringSlice(name); !defaults || len(v) > 0 {
+				*field = v
+			}
+		case *map[string]string:
+			if v, _ := f.GetStringToString(name); !defaults || len(v) > 0 {
+				*field = v
+			}
+		}
+	}
+	return c
+}
+
+// flagsLayer is the name of the command-line flags layer.
+const flagsLayer = "command line"
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect configuration",
+	Long: `Settings are read from ` + config.SystemPath + `, then the user's config
+file ($XDG_CONFIG_HOME/pwdforge/config.yaml) or --config, then the section
+of the --profile in those files, then PWDFORGE_* environment variables.
+Command-line flags override them all.`,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show [command...]",
+	Short: "Print the settings the config files and environment give a command",
+	Long: `Prints the settings that the config files, the --profile and PWDFORGE_*
+environment variables give a command, generate by default, as YAML in the
+config file format.
+
+With --resolved, prints every setting after applying the layers in order
+of precedence - built-in defaults, ` + config.SystemPath + `, the user's
+config file or --config, the profile, then the environment - with a
+comment naming the layer each value came from. Command-line flags (and,
+for generate, an --input entry) override these in turn.
+
+Examples:
+  pwdforge config show --resolved
+  pwdforge config show checkpwn --profile ci`,
+	Run: func(cmd *cobra.Command, args []string) {
+		resolved, _ := cmd.Flags().GetBool("resolved")
+
+		target := generateCmd
+		if len(args) > 0 {
+			c, rest, err := RootCmd.Find(args)
+			if err != nil || len(rest) > 0 || c == RootCmd || !usesConfig(c) {
+				fmt.Fprintf(os.Stderr, "Error: unknown command %q\n", strings.Join(args, " "))
+				os.Exit(1)
+			}
+			target = c
+		}
+
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		if target == generateCmd {
+			layers, vars, err := generateConfigLayers()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+				os.Exit(1)
+			}
+			if !resolved {
+				layers = layers[1:]
+			}
+			var c GenerateConfig
+			sources := config.Resolve(&c, layers)
+			if !resolved {
+				sources = nil
+			} else if c.Length == nil {
+				// Shown for completeness; generate leaves it to the library.
+				length := pwdforge.DefaultLength
+				if value(c.PIN) {
+					length = pwdforge.DefaultPINLength
+				}
+				c.Length = &length
+				sources["length"] = config.Default
+			}
+			for key, source := range sources {
+				if source == envLayer {
+					sources[key] = envSource(key, vars)
+				}
+			}
+			if node, err = config.Node(&c, sources); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if flags := commandNode(target, resolved); len(flags.Content) > 0 {
+			section := flags
+			path := commandPath(target)
+			for i := len(path) - 1; i >= 0; i-- {
+				section = &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+					{Kind: yaml.ScalarNode, Value: path[i]}, section,
+				}}
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "commands"}, section)
+		}
+		if len(node.Content) == 0 {
+			fmt.Fprintln(os.Stderr, "[!] No settings; run with --resolved to include the defaults.")
+			return
+		}
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(node); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		enc.Close()
+	},
+}
+
+// envSource names the environment variables a generate setting was read
+// from, e.g. "$PWDFORGE_POLICY_MIN_LENGTH" for policy.
+func envSource(key string, vars map[string]string) string {
+	var names []string
+	for path, name := range vars {
+		if path == key || strings.HasPrefix(path, key+".") {
+			names = append(names, "$"+name)
+		}
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
+
+func init() {
+	configShowCmd.Flags().Bool("resolved", false, "Include the defaults and name the source of every value")
+	configCmd.AddCommand(configShowCmd)
+	RootCmd.AddCommand(configCmd)
+}
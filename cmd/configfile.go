@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"pwdforge/internal/config"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// configFile is the layout of config files. Generate settings are at the
+// top level; other commands read flag defaults from commands; profiles
+// holds named sections of both, applied with --profile.
+type configFile struct {
+	configSection `yaml:",inline"`
+	Profiles      map[string]configSection `yaml:"profiles"`
+}
+
+// configSection is a config file, or one of its profiles.
+type configSection struct {
+	GenerateConfig `yaml:",inline"`
+	// Commands maps command names to their flag defaults, with the flag
+	// names spelled with "_" or "-", and to sections of their subcommands,
+	// e.g. checkpwn: {api_url: ...} or policy: {check: {format: json}}.
+	Commands yaml.Node `yaml:"commands"`
+}
+
+// configLayer is a loaded config file or profile.
+type configLayer struct {
+	// name is the file, followed by the profile for profiles.
+	name string
+	configSection
+}
+
+// activeConfig holds the config layers of the running command, in order
+// of precedence. They are loaded before the command runs.
+var activeConfig []configLayer
+
+// envLayer is the name of the PWDFORGE_* environment variables layer.
+const envLayer = "environment"
+
+// loadConfigLayers reads the system config file and then the user's, or
+// configFile instead of the user's, followed by the profile sections of
+// both. Missing files are skipped unless configFile is missing.
+func loadConfigLayers(configFile, profile string) ([]configLayer, error) {
+	paths := []string{config.SystemPath}
+	switch {
+	case configFile != "":
+		paths = append(paths, configFile)
+	case config.UserPath() != "":
+		paths = append(paths, config.UserPath())
+	}
+	var layers, profiles []configLayer
+	var read []string
+	for _, path := range paths {
+		f, err := readConfigFile(path)
+		if errors.Is(err, fs.ErrNotExist) && path != configFile {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		read = append(read, path)
+		layers = append(layers, configLayer{name: path, configSection: f.configSection})
+		if p, ok := f.Profiles[profile]; ok && profile != "" {
+			profiles = append(profiles, configLayer{name: fmt.Sprintf("%s (profile %s)", path, profile), configSection: p})
+		}
+	}
+	if profile != "" && len(profiles) == 0 {
+		if len(read) == 0 {
+			return nil, fmt.Errorf("profile %q: no config file found", profile)
+		}
+		return nil, fmt.Errorf("profile %q is not defined in %s", profile, strings.Join(read, " or "))
+	}
+	layers = append(layers, profiles...)
+	for _, l := range layers {
+		if err := checkCommandSection(&l.Commands, RootCmd); err != nil {
+			return nil, fmt.Errorf("%s: %w", l.name, err)
+		}
+	}
+	return layers, nil
+}
+
+// readConfigFile reads a config file. Unknown keys are errors, so that a
+// misspelt setting is reported rather than silently left at its default.
+func readConfigFile(path string) (*configFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var cfg configFile
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// checkCommandSection checks that every key of the commands section node
+// for cmd names a subcommand or a flag that config files may set.
+func checkCommandSection(node *yaml.Node, cmd *cobra.Command) error {
+	if node.Kind == 0 {
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: the %s section must be a mapping", node.Line, sectionName(cmd))
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if sub := subcommand(cmd, key.Value); sub != nil && value.Kind == yaml.MappingNode {
+			if err := checkCommandSection(value, sub); err != nil {
+				return err
+			}
+			continue
+		}
+		if cmd == RootCmd {
+			return fmt.Errorf("line %d: unknown command %q in commands", key.Line, key.Value)
+		}
+		f := configFlag(cmd, key.Value)
+		switch {
+		case f == nil:
+			return fmt.Errorf("line %d: %s has no setting %q", key.Line, sectionName(cmd), key.Value)
+		case cmd == generateCmd && isGenerateKeyFlag(f.Name):
+			return fmt.Errorf("line %d: %s is a generate setting; put it at the top level of the file", key.Line, key.Value)
+		}
+		if _, err := config.FlagValue(value); err != nil {
+			return fmt.Errorf("%s.%s: %w", sectionName(cmd), key.Value, err)
+		}
+	}
+	return nil
+}
+
+// sectionName names the commands section of cmd, e.g. commands.policy.check.
+func sectionName(cmd *cobra.Command) string {
+	return strings.Join(append([]string{"commands"}, commandPath(cmd)...), ".")
+}
+
+// commandPath returns the names of cmd and its parents below the root.
+func commandPath(cmd *cobra.Command) []string {
+	var path []string
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		path = append([]string{c.Name()}, path...)
+	}
+	return path
+}
+
+func subcommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, c := range cmd.Commands() {
+		if c.Name() == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// configFlag returns the flag of cmd that the config key sets, or nil.
+// --config, --profile and --help cannot be set from config files.
+func configFlag(cmd *cobra.Command, key string) *pflag.Flag {
+	name := strings.ReplaceAll(key, "_", "-")
+	if slices.Contains([]string{"config", "profile", "help"}, name) {
+		return nil
+	}
+	return cmd.Flags().Lookup(name)
+}
+
+// flagSetting is a flag value from a config file or the environment.
+type flagSetting struct {
+	value  string
+	source string
+}
+
+// commandSettings collects the flag values of cmd from the commands
+// sections of layers and from PWDFORGE_<COMMAND>_<FLAG> environment
+// variables, later ones overriding earlier ones, keyed by flag name.
+// Generate flags that are config keys are left to the generate layers.
+func commandSettings(cmd *cobra.Command, layers []configLayer) map[string]flagSetting {
+	path := commandPath(cmd)
+	settings := map[string]flagSetting{}
+	for _, l := range layers {
+		node := &l.Commands
+		for _, name := range path {
+			node = mappingValue(node, name)
+		}
+		if node == nil {
+			continue
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			f := configFlag(cmd, key.Value)
+			if f == nil || value.Kind == yaml.MappingNode && subcommand(cmd, key.Value) != nil {
+				continue
+			}
+			// Checked when the layers were loaded.
+			v, _ := config.FlagValue(value)
+			settings[f.Name] = flagSetting{value: v, source: l.name}
+		}
+	}
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if configFlag(cmd, f.Name) == nil || (cmd == generateCmd && isGenerateKeyFlag(f.Name)) {
+			return
+		}
+		name := config.EnvName(append(path, f.Name)...)
+		if v, ok := os.LookupEnv(name); ok {
+			settings[f.Name] = flagSetting{value: v, source: "$" + name}
+		}
+	})
+	return settings
+}
+
+// mappingValue returns the mapping stored under key in the mapping node,
+// or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key && node.Content[i+1].Kind == yaml.MappingNode {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// commandNode renders the flag settings of cmd as a YAML mapping keyed as
+// in config files. With resolved it includes the defaults of the other
+// flags, and each key is followed by a comment naming its source.
+func commandNode(cmd *cobra.Command, resolved bool) *yaml.Node {
+	settings := commandSettings(cmd, activeConfig)
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if configFlag(cmd, f.Name) == nil || (cmd == generateCmd && isGenerateKeyFlag(f.Name)) {
+			return
+		}
+		s, ok := settings[f.Name]
+		switch {
+		case ok:
+		case resolved:
+			s = flagSetting{value: f.DefValue, source: config.Default}
+		default:
+			return
+		}
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: strings.ReplaceAll(f.Name, "-", "_")}
+		value := flagNode(f, s.value)
+		if resolved {
+			// The encoder drops key comments before flow lists and maps.
+			value.LineComment = s.source
+		}
+		node.Content = append(node.Content, key, value)
+	})
+	return node
+}
+
+// flagNode renders the value of flag f, as set in a config file or shown
+// by pflag as a default, as YAML: a list for slice flags, a mapping for
+// map flags and a scalar otherwise.
+func flagNode(f *pflag.Flag, v string) *yaml.Node {
+	typ := f.Value.Type()
+	isMap := strings.HasPrefix(typ, "stringTo")
+	if !isMap && !strings.HasSuffix(typ, "Slice") && !strings.HasSuffix(typ, "Array") {
+		node := &yaml.Node{Kind: yaml.ScalarNode, Value: v}
+		if v == "" {
+			node.Style = yaml.DoubleQuotedStyle
+		}
+		return node
+	}
+	items := splitFlagList(strings.TrimSuffix(strings.TrimPrefix(v, "["), "]"))
+	if !isMap {
+		node := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, item := range items {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: item})
+		}
+		return node
+	}
+	node := &yaml.Node{Kind: yaml.MappingNode, Style: yaml.FlowStyle}
+	for _, pair := range items {
+		k, val, _ := strings.Cut(pair, "=")
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: k}, &yaml.Node{Kind: yaml.ScalarNode, Value: val})
+	}
+	return node
+}
+
+// splitFlagList splits a comma-separated flag value; "" is empty.
+func splitFlagList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// applyConfig loads the config layers for cmd and sets the flags that the
+// command line leaves unset from them, so commands read config files and
+// environment variables through their flags.
+func applyConfig(cmd *cobra.Command) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	if configFile == "" {
+		configFile = os.Getenv(config.EnvName("config"))
+	}
+	profile, _ := cmd.Flags().GetString("profile")
+	if profile == "" {
+		profile = os.Getenv(config.EnvName("profile"))
+	}
+	layers, err := loadConfigLayers(configFile, profile)
+	if err != nil {
+		return err
+	}
+	activeConfig = layers
+	for name, s := range commandSettings(cmd, layers) {
+		f := cmd.Flags().Lookup(name)
+		if f.Changed {
+			continue
+		}
+		// Value.Set leaves the flag unchanged, so a setting is still a
+		// default as far as the command can tell.
+		if err := f.Value.Set(s.value); err != nil {
+			return fmt.Errorf("%s: invalid %s value %q: %v", s.source, strings.ReplaceAll(name, "-", "_"), s.value, err)
+		}
+	}
+	return nil
+}
+
+// usesConfig reports whether cmd reads config files: not help, shell
+// completion or hidden helper commands, which must work even when a
+// config file is broken.
+func usesConfig(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Hidden || c.Name() == "help" || c.Name() == "completion" {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	RootCmd.PersistentFlags().String("config", "", "Config file (default: "+config.SystemPath+", then $XDG_CONFIG_HOME/pwdforge/config.yaml; also $"+config.EnvName("config")+")")
+	RootCmd.PersistentFlags().String("profile", "", "Apply this profile from the config files (also $"+config.EnvName("profile")+")")
+	RootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		if !usesConfig(cmd) {
+			return
+		}
+		if err := applyConfig(cmd); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
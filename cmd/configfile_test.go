@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pwdforge/internal/config"
+
+	"github.com/spf13/pflag"
+)
+
+const testConfig = `length: 16
+count: 2
+include_digits: false
+commands:
+  checkpwn:
+    api_url: https://file.example
+    format: json
+profiles:
+  ci:
+    length: 24
+    commands:
+      checkpwn:
+        api_url: https://profile.example
+`
+
+// writeConfig writes content to a config file and returns its path. It
+// skips the test when a system config file would add a layer.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	if _, err := os.Stat(config.SystemPath); err == nil {
+		t.Skip("a system config file exists:", config.SystemPath)
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// resolveGenerate resolves the generate settings from layers and the
+// command-line args, as generate does.
+func resolveGenerate(t *testing.T, layers []configLayer, args ...string) (GenerateConfig, config.Sources) {
+	t.Helper()
+	saved := activeConfig
+	activeConfig = layers
+	t.Cleanup(func() { activeConfig = saved })
+	generateLayers, _, err := generateConfigLayers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	flags := pflag.NewFlagSet("generate", pflag.ContinueOnError)
+	flags.IntP("length", "l", 20, "")
+	flags.Int("count", 1, "")
+	if err := flags.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	cmdLine := generateFlagLayer(flags, false)
+	var c GenerateConfig
+	sources := config.Resolve(&c, append(generateLayers, config.Layer{Name: flagsLayer, Values: &cmdLine}))
+	return c, sources
+}
+
+// TestConfigPrecedence checks that flags override the environment, which
+// overrides the profile, which overrides the config file, which overrides
+// the built-in defaults.
+func TestConfigPrecedence(t *testing.T) {
+	path := writeConfig(t, testConfig)
+	profile := path + " (profile ci)"
+	tests := []struct {
+		name    string
+		profile string
+		env     map[string]string
+		args    []string
+		length  int
+		source  string
+	}{
+		{"file", "", nil, nil, 16, path},
+		{"profile", "ci", nil, nil, 24, profile},
+		{"environment", "ci", map[string]string{"PWDFORGE_LENGTH": "32"}, nil, 32, envLayer},
+		{"flag", "ci", map[string]string{"PWDFORGE_LENGTH": "32"}, []string{"--length", "40"}, 40, flagsLayer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			layers, err := loadConfigLayers(path, tt.profile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, sources := resolveGenerate(t, layers, tt.args...)
+			if *c.Length != tt.length || sources["length"] != tt.source {
+				t.Errorf("length = %d from %s, want %d from %s", *c.Length, sources["length"], tt.length, tt.source)
+			}
+			// Keys no higher layer sets keep their lower source.
+			if *c.Count != 2 || sources["count"] != path {
+				t.Errorf("count = %d from %s, want 2 from the file", *c.Count, sources["count"])
+			}
+			if *c.IncludeDigits || sources["include_digits"] != path {
+				t.Errorf("include_digits = %v from %s, want false from the file", *c.IncludeDigits, sources["include_digits"])
+			}
+			if !*c.IncludeUpper || sources["include_upper"] != config.Default {
+				t.Errorf("include_upper = %v from %s, want the default", *c.IncludeUpper, sources["include_upper"])
+			}
+		})
+	}
+}
+
+func TestCommandSettingsPrecedence(t *testing.T) {
+	path := writeConfig(t, testConfig)
+	layers, err := loadConfigLayers(path, "ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := commandSettings(checkpwnCmd, layers)
+	if s := settings["api-url"]; s.value != "https://profile.example" || s.source != path+" (profile ci)" {
+		t.Errorf("api-url = %+v, want the profile's", s)
+	}
+	if s := settings["format"]; s.value != "json" || s.source != path {
+		t.Errorf("format = %+v, want the file's", s)
+	}
+
+	t.Setenv("PWDFORGE_CHECKPWN_API_URL", "https://env.example")
+	settings = commandSettings(checkpwnCmd, layers)
+	if s := settings["api-url"]; s.value != "https://env.example" || s.source != "$PWDFORGE_CHECKPWN_API_URL" {
+		t.Errorf("api-url = %+v, want the environment's", s)
+	}
+}
+
+func TestConfigUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name, content, profile, want string
+	}{
+		{"top level", "length: 16\nlenght: 20\n", "",
+			"line 2: field lenght not found"},
+		{"profile", "profiles:\n  ci:\n    count: 2\n    cuont: 3\n", "ci",
+			"line 4: field cuont not found"},
+		{"unknown command", "commands:\n  checkpwnd:\n    format: json\n", "",
+			`line 2: unknown command "checkpwnd" in commands`},
+		{"unknown setting", "commands:\n  checkpwn:\n    fromat: json\n", "",
+			`line 3: commands.checkpwn has no setting "fromat"`},
+		{"unknown subcommand setting", "commands:\n  policy:\n    check:\n      colour: red\n", "",
+			`line 4: commands.policy.check has no setting "colour"`},
+		{"generate key in commands", "commands:\n  generate:\n    length: 16\n", "",
+			"line 3: length is a generate setting; put it at the top level of the file"},
+		{"undefined profile", "length: 16\n", "prod",
+			`profile "prod" is not defined in `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.content)
+			_, err := loadConfigLayers(path, tt.profile)
+			if err == nil || !strings.Contains(err.Error(), path) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("loadConfigLayers = %v, want an error naming %s and containing %q", err, path, tt.want)
+			}
+		})
+	}
+}
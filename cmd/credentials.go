@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"pwdforge/internal/credfile"
+	"pwdforge/pkg/pwdforge"
+
+	"github.com/spf13/cobra"
+)
+
+// credentialFormat describes a "user:hash[:...]" file handled by
+// newCredentialCommand.
+type credentialFormat struct {
+	name       string
+	short      string
+	long       string
+	algorithms []string
+	defaultAlg string
+	// entry builds the line for user from a fresh hash. old holds the
+	// user's current fields, or nil for a new user.
+	entry func(user, hash string, old []string) string
+}
+
+// newCredentialCommand builds "<name> add|verify|rm <file> <user>".
+func newCredentialCommand(f credentialFormat) *cobra.Command {
+	root := &cobra.Command{
+		Use:   f.name,
+		Short: f.short,
+		Long:  f.long,
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add <file> <user>",
+		Short: "Add a user or rotate their password",
+		Long: `Generates a password with the usual generator options (or reads one with
+--ask-password), hashes it and writes the entry, replacing any existing entry
+for the user. The file is rewritten atomically and created if missing. The
+generated password is printed on stdout.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			path, user := args[0], args[1]
+			alg, _ := cmd.Flags().GetString("algorithm")
+			ask, _ := cmd.Flags().GetBool("ask-password")
+			copyClip, _ := cmd.Flags().GetBool("clipboard")
+			clipTimeout, _ := cmd.Flags().GetDuration("clipboard-timeout")
+			if err := credfile.ValidUser(user); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if !slices.Contains(f.algorithms, alg) {
+				fmt.Fprintf(os.Stderr, "Error: unsupported algorithm %q for %s (want one of %s)\n", alg, f.name, strings.Join(f.algorithms, ", "))
+				os.Exit(1)
+			}
+
+			var password string
+			if ask {
+				pw, err := readNewSecret("Password: ")
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error reading password: %v\n", err)
+					os.Exit(1)
+				}
+				if len(pw) == 0 {
+					fmt.Fprintln(os.Stderr, "Error: Password cannot be empty.")
+					os.Exit(1)
+				}
+				password = string(pw)
+			} else {
+				pws, _, err := generateFromOptions(context.Background(), passwordOptionsFromFlags(cmd))
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error generating password: %v\n", err)
+					os.Exit(1)
+				}
+				password = pws[0]
+			}
+
+			h, err := pwdforge.Hash(alg, password)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error hashing password: %v\n", err)
+				os.Exit(1)
+			}
+			updated := false
+			err = credfile.Update(path, 0640, func(lines []string) ([]string, error) {
+				if i := credfile.Find(lines, user); i >= 0 {
+					updated = true
+					lines[i] = f.entry(user, h, strings.Split(lines[i], ":"))
+					return lines, nil
+				}
+				return append(lines, f.entry(user, h, nil)), nil
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error updating %s: %v\n", path, err)
+				os.Exit(1)
+			}
+			if updated {
+				fmt.Fprintf(os.Stderr, "[+] Updated password for %s in %s\n", user, path)
+			} else {
+				fmt.Fprintf(os.Stderr, "[+] Added %s to %s\n", user, path)
+			}
+			if !ask {
+				fmt.Println(password)
+				if copyClip {
+					if err := copySecret(password, clipTimeout); err != nil {
+						fmt.Fprintf(os.Stderr, "[!] Failed to copy to clipboard: %v\n", err)
+					}
+				}
+			}
+		},
+	}
+	addCmd.Flags().StringP("algorithm", "a", f.defaultAlg, "Hash algorithm: "+strings.Join(f.algorithms, ", "))
+	addCmd.Flags().Bool("ask-password", false, "Read the password from a hidden prompt instead of generating one")
+	addCmd.Flags().Bool("clipboard", false, "Copy the generated password to the clipboard")
+	addCmd.Flags().Duration("clipboard-timeout", defaultClipboardTimeout, "Clear the clipboard after this long (0 to keep)")
+	addPasswordFlags(addCmd)
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify <file> <user>",
+		Short: "Check a password against the user's entry",
+		Long:  "Reads a password without echoing it (or from stdin when piped) and exits 1 if it does not match.",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			path, user := args[0], args[1]
+			fields, err := credfile.Lookup(path, user)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s: %v\n", user, err)
+				os.Exit(1)
+			}
+			if len(fields) < 2 {
+				fmt.Fprintf(os.Stderr, "Error: malformed entry for %s\n", user)
+				os.Exit(1)
+			}
+			password, err := readSecret("Password: ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading password: %v\n", err)
+				os.Exit(1)
+			}
+			ok, err := pwdforge.VerifyHash(fields[1], string(password))
+			if err != nil && !errors.Is(err, pwdforge.ErrUnsupportedHash) {
+				fmt.Fprintf(os.Stderr, "Error verifying password: %v\n", err)
+				os.Exit(1)
+			}
+			if errors.Is(err, pwdforge.ErrUnsupportedHash) {
+				// Locked (!, *) and empty shadow entries land here too.
+				fmt.Fprintf(os.Stderr, "[!] Entry for %s is locked or uses an unsupported hash\n", user)
+				os.Exit(1)
+			}
+			if !ok {
+				fmt.Fprintln(os.Stderr, "[!] Password does not match")
+				os.Exit(1)
+			}
+			fmt.Fprintln(os.Stderr, "[+] Password matches")
+		},
+	}
+
+	rmCmd := &cobra.Command{
+		Use:   "rm <file> <user>",
+		Short: "Remove a user's entry",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			path, user := args[0], args[1]
+			err := credfile.Update(path, 0640, func(lines []string) ([]string, error) {
+				i := credfile.Find(lines, user)
+				if i < 0 {
+					return nil, fmt.Errorf("%s: %w", user, credfile.ErrNoUser)
+				}
+				return slices.Delete(lines, i, i+1), nil
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error updating %s: %v\n", path, err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "[+] Removed %s from %s\n", user, path)
+		},
+	}
+
+	root.AddCommand(addCmd, verifyCmd, rmCmd)
+	return root
+}
+
+func init() {
+	RootCmd.AddCommand(newCredentialCommand(credentialFormat{
+		name:  "htpasswd",
+		short: "Manage Apache/nginx basic-auth password files",
+		long: `Adds, verifies and removes users in an htpasswd file. bcrypt hashes are
+written with the $2y$ prefix Apache expects; apr1 is the legacy Apache MD5
+format; the SHA-crypt formats are accepted by Apache 2.4 and nginx on Linux.`,
+		algorithms: []string{"bcrypt", "apr1", "sha512crypt", "sha256crypt"},
+		defaultAlg: "bcrypt",
+		entry: func(user, hash string, old []string) string {
+			if strings.HasPrefix(hash, "$2a$") {
+				hash = "$2y$" + strings.TrimPrefix(hash, "$2a$")
+			}
+			return user + ":" + hash
+		},
+	}))
+
+	RootCmd.AddCommand(newCredentialCommand(credentialFormat{
+		name:  "shadow",
+		short: "Manage shadow(5) format password files",
+		long: `Adds, verifies and removes users in a file in /etc/shadow format, e.g. for
+container images or chroots. Rotating a password keeps the user's aging fields
+and sets the last-change date to today.`,
+		algorithms: []string{"sha512crypt", "sha256crypt", "bcrypt"},
+		defaultAlg: "sha512crypt",
+		entry: func(user, hash string, old []string) string {
+			lastChange := fmt.Sprint(time.Now().Unix() / 86400)
+			if len(old) < 9 {
+				// user:hash:lastchg:min:max:warn:inactive:expire:reserved
+				return user + ":" + hash + ":" + lastChange + ":0:99999:7:::"
+			}
+			old[1], old[2] = hash, lastChange
+			return strings.Join(old, ":")
+		},
+	}))
+}
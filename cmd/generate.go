@@ -1,288 +1,261 @@
 package cmd
 
 import (
-	"bufio"
-	"encoding/json"
+	"context"
 	"fmt"
 	"os"
+	"runtime"
+	"slices"
 	"strings"
+	"time"
 
-	"math/rand"
-	"pwdforge/internal/generator"
+	"pwdforge/internal/batch"
+	"pwdforge/internal/config"
+	"pwdforge/pkg/pwdforge"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var generateCmd = &cobra.Command{
 	Use:   "generate",
 	Short: "Generate one or more secure passwords",
 	Run: func(cmd *cobra.Command, args []string) {
-		length, _ := cmd.Flags().GetInt("length")
-		count, _ := cmd.Flags().GetInt("count")
-		includeUpper, _ := cmd.Flags().GetBool("uppercase")
-		includeLower, _ := cmd.Flags().GetBool("lowercase")
-		includeDigits, _ := cmd.Flags().GetBool("digits")
-		includeSpecials, _ := cmd.Flags().GetBool("specials")
-		excludeSimilar, _ := cmd.Flags().GetBool("exclude-similar")
 		outputFile, _ := cmd.Flags().GetString("output")
 		verbose, _ := cmd.Flags().GetBool("verbose")
 		format, _ := cmd.Flags().GetString("format")
-		customCharset, _ := cmd.Flags().GetString("custom-charset")
-		usePassphrase, _ := cmd.Flags().GetBool("passphrase")
-		enforceAll, _ := cmd.Flags().GetBool("enforce-all")
 		inputFile, _ := cmd.Flags().GetString("input")
-		configFile, _ := cmd.Flags().GetString("config")
+		inputFormat, _ := cmd.Flags().GetString("input-format")
+		strict, _ := cmd.Flags().GetBool("strict")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		policyFile, _ := cmd.Flags().GetString("policy")
+		preset, _ := cmd.Flags().GetString("preset")
+		checkPreset, _ := cmd.Flags().GetString("check-preset")
 		copyClip, _ := cmd.Flags().GetBool("clipboard")
-		wordCount, _ := cmd.Flags().GetInt("word-count")
 
-		// Load config file if provided
-		var cfg *GenerateConfig
-		if configFile != "" {
-			var err error
-			cfg, err = loadGenerateConfig(configFile)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error loading config file: %v\n", err)
+		// Settings come from layers, each overriding the ones before:
+		// built-in defaults, the config files and profile, PWDFORGE_*
+		// environment variables, the --input entry and the command-line
+		// flags.
+		layers, _, err := generateConfigLayers()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		var base GenerateConfig
+		config.Resolve(&base, layers)
+		flags := generateFlagLayer(cmd.Flags(), false)
+		// run holds the settings of the whole run, which --input entries
+		// cannot change.
+		run := base
+		config.Merge(&run, &flags, flagsLayer, nil)
+
+		hashAlg, hashOnly := value(run.Hash), value(run.HashOnly)
+		workers := value(run.Workers)
+		clipTimeout, err := time.ParseDuration(value(run.ClipboardTimeout))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid clipboard_timeout in config: %v\n", err)
+			os.Exit(1)
+		}
+
+		var flagPol *pwdforge.Policy
+		if policyFile != "" || preset != "" {
+			if flagPol, err = resolvePolicy(policyFile, preset); err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading policy: %v\n", err)
 				os.Exit(1)
 			}
 		}
-		// Only use config values if CLI flag is not set (flags take precedence)
-		if cfg != nil {
-			if !cmd.Flags().Changed("length") && cfg.Length > 0 {
-				length = cfg.Length
-			}
-			if !cmd.Flags().Changed("count") && cfg.Count > 0 {
-				count = cfg.Count
-			}
-			if !cmd.Flags().Changed("uppercase") {
-				includeUpper = cfg.IncludeUpper
+		// Banned words reject generated passwords through the policy. They
+		// and the context values mark matching passwords Weak in verbose
+		// output; context values are rejected by the library.
+		words := pwdforge.NewBlocklist()
+		for _, path := range run.BannedWordFiles {
+			if err := words.LoadFile(path); err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading banned words: %v\n", err)
+				os.Exit(1)
 			}
-			if !cmd.Flags().Changed("lowercase") {
-				includeLower = cfg.IncludeLower
+		}
+		banned := words
+		if len(run.Context) > 0 {
+			banned = pwdforge.NewBlocklist()
+			banned.Merge(words)
+			for _, v := range run.Context {
+				banned.AddContext(v)
 			}
-			if !cmd.Flags().Changed("digits") {
-				includeDigits = cfg.IncludeDigits
+		}
+		var checkPol *pwdforge.Policy
+		if checkPreset != "" {
+			if !verbose {
+				fmt.Fprintln(os.Stderr, "Error: --check-preset reports compliance in --verbose output; add --verbose.")
+				os.Exit(1)
 			}
-			if !cmd.Flags().Changed("specials") {
-				includeSpecials = cfg.IncludeSpecials
+			if checkPol, err = loadPreset(checkPreset); err == nil {
+				// Load its word lists once rather than per password.
+				err = checkPol.Prepare()
 			}
-			if !cmd.Flags().Changed("exclude-similar") {
-				excludeSimilar = cfg.ExcludeSimilar
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading preset: %v\n", err)
+				os.Exit(1)
 			}
-			if !cmd.Flags().Changed("custom-charset") && cfg.CustomCharset != "" {
-				customCharset = cfg.CustomCharset
+		}
+
+		if hashOnly && hashAlg == "" {
+			fmt.Fprintln(os.Stderr, "Error: --hash-only requires --hash.")
+			os.Exit(1)
+		}
+		if hashAlg != "" && !slices.Contains(pwdforge.HashAlgorithms(), hashAlg) {
+			fmt.Fprintf(os.Stderr, "Error: unknown hash algorithm %q (want %s)\n", hashAlg, strings.Join(pwdforge.HashAlgorithms(), ", "))
+			os.Exit(1)
+		}
+		switch {
+		case workers < 0:
+			fmt.Fprintln(os.Stderr, "Error: --workers must not be negative.")
+			os.Exit(1)
+		case workers == 0:
+			workers = runtime.GOMAXPROCS(0)
+		}
+
+		// resolve applies an --input entry (nil for none) and then the
+		// flags over the config layers. --policy replaces the layers'
+		// policy, while --preset and banned words tighten it.
+		resolve := func(line *batchLine) generateEntry {
+			opts := base.GenerateOptions
+			var e generateEntry
+			if line != nil {
+				config.Merge(&opts, &line.GenerateOptions, "", nil)
 			}
-			if !cmd.Flags().Changed("enforce-all") {
-				enforceAll = cfg.EnforceAll
+			config.Merge(&opts, &flags.GenerateOptions, flagsLayer, nil)
+			e.Options = opts.Options()
+			if line != nil {
+				e.label, e.Username = value(line.Label), value(line.Username)
 			}
-			if !cmd.Flags().Changed("passphrase") {
-				usePassphrase = cfg.Passphrase
+			switch {
+			case policyFile != "":
+				e.Policy = flagPol
+			case preset != "":
+				e.Policy = pwdforge.Strictest(e.Policy, flagPol)
 			}
-			if !cmd.Flags().Changed("word-count") && cfg.WordCount > 0 {
-				wordCount = cfg.WordCount
+			if words.Len() > 0 {
+				e.Policy = pwdforge.Strictest(e.Policy, &pwdforge.Policy{Blocklist: words})
 			}
+			return e
 		}
 
-		var passwords []string
-		if inputFile != "" {
-			file, err := os.Open(inputFile)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error opening input file: %v\n", err)
-				os.Exit(1)
+		// With --strict or --dry-run the whole --input file is checked
+		// before anything is generated, and every problem is reported.
+		if dryRun || (strict && inputFile != "") {
+			entries, secrets, problems := 0, 0, 0
+			problem := func(err error) {
+				problems++
+				fmt.Fprintf(os.Stderr, "[!] %v\n", err)
 			}
-			defer file.Close()
-			scanner := bufio.NewScanner(file)
-			for scanner.Scan() {
-				line := strings.TrimSpace(scanner.Text())
-				if line == "" || strings.HasPrefix(line, "#") {
-					continue
-				}
-				// Try JSON first
-				var params GenerateConfig
-				if err := json.Unmarshal([]byte(line), &params); err != nil {
-					// Try YAML if JSON fails
-					err2 := yaml.Unmarshal([]byte(line), &params)
-					if err2 != nil {
-						fmt.Fprintf(os.Stderr, "Skipping invalid input line: %s\n", line)
-						continue
-					}
-				}
-				// Merge params with CLI/config defaults
-				merged := GenerateConfig{
-					Length:          params.Length,
-					Count:           params.Count,
-					IncludeUpper:    params.IncludeUpper,
-					IncludeLower:    params.IncludeLower,
-					IncludeDigits:   params.IncludeDigits,
-					IncludeSpecials: params.IncludeSpecials,
-					ExcludeSimilar:  params.ExcludeSimilar,
-					CustomCharset:   params.CustomCharset,
-					EnforceAll:      params.EnforceAll,
-					Passphrase:      params.Passphrase,
-					WordCount:       params.WordCount,
-				}
-				if merged.Length == 0 {
-					merged.Length = length
-				}
-				if merged.Count == 0 {
-					merged.Count = 1
+			if inputFile == "" {
+				e := resolve(nil)
+				if err := e.Validate(); err != nil {
+					problem(err)
 				}
-				if !params.IncludeUpper && !params.IncludeLower && !params.IncludeDigits && !params.IncludeSpecials && params.CustomCharset == "" {
-					merged.IncludeUpper = includeUpper
-					merged.IncludeLower = includeLower
-					merged.IncludeDigits = includeDigits
-					merged.IncludeSpecials = includeSpecials
-				}
-				if merged.CustomCharset == "" {
-					merged.CustomCharset = customCharset
-				}
-				if !merged.EnforceAll {
-					merged.EnforceAll = enforceAll
-				}
-				if !merged.Passphrase {
-					merged.Passphrase = usePassphrase
-				}
-				if merged.WordCount == 0 {
-					merged.WordCount = wordCount
-				}
-				// Use merged config to generate password(s)
-				if merged.Passphrase {
-					wc := merged.WordCount
-					if wc <= 0 {
-						wc = 4
-					}
-					passwords = append(passwords, GeneratePassphrase(wc, nil))
-				} else {
-					cfg := generator.PasswordConfig{
-						Length:          merged.Length,
-						Count:           merged.Count,
-						IncludeUpper:    merged.IncludeUpper,
-						IncludeLower:    merged.IncludeLower,
-						IncludeDigits:   merged.IncludeDigits,
-						IncludeSpecials: merged.IncludeSpecials,
-						ExcludeSimilar:  merged.ExcludeSimilar,
+				entries, secrets = 1, max(e.Count, 1)
+			} else {
+				err := readBatch(inputFile, inputFormat, func(l *batchLine, e batch.Entry) error {
+					c := resolve(l)
+					if err := c.Validate(); err != nil {
+						problem(entryError(e, err))
+						return nil
 					}
-					if merged.CustomCharset != "" {
-						pw := make([]byte, merged.Length)
-						for j := 0; j < merged.Length; j++ {
-							idx := RandomInt(len(merged.CustomCharset))
-							pw[j] = merged.CustomCharset[idx]
-						}
-						passwords = append(passwords, string(pw))
+					entries++
+					secrets += max(c.Count, 1)
+					return nil
+				}, func(p batchProblem) error {
+					if p.unknownKey && !strict {
+						fmt.Fprintf(os.Stderr, "[!] %v (ignored)\n", p.Error)
 					} else {
-						passwords = append(passwords, generator.GeneratePasswords(cfg)...)
+						problem(p.Error)
 					}
+					return nil
+				})
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error reading input file: %v\n", err)
+					os.Exit(1)
 				}
 			}
-			if err := scanner.Err(); err != nil {
-				fmt.Fprintf(os.Stderr, "Error reading input file: %v\n", err)
+			if problems > 0 {
+				fmt.Fprintf(os.Stderr, "Error: %d problem(s) found; nothing was generated.\n", problems)
 				os.Exit(1)
 			}
-		} else {
-			if usePassphrase {
-				wc := wordCount
-				if wc <= 0 {
-					wc = 4
-				}
-				passwords = []string{}
-				for i := 0; i < count; i++ {
-					passwords = append(passwords, GeneratePassphrase(wc, nil))
-				}
-			} else {
-				cfg := generator.PasswordConfig{
-					Length:          length,
-					Count:           count,
-					IncludeUpper:    includeUpper,
-					IncludeLower:    includeLower,
-					IncludeDigits:   includeDigits,
-					IncludeSpecials: includeSpecials,
-					ExcludeSimilar:  excludeSimilar,
-				}
-				if customCharset != "" {
-					passwords = []string{}
-					for i := 0; i < count; i++ {
-						pw := make([]byte, length)
-						for j := 0; j < length; j++ {
-							idx := RandomInt(len(customCharset))
-							pw[j] = customCharset[idx]
-						}
-						passwords = append(passwords, string(pw))
-					}
+			if dryRun {
+				if inputFile == "" {
+					fmt.Fprintf(os.Stdout, "[+] Options valid; would generate %d secrets\n", secrets)
 				} else {
-					passwords = generator.GeneratePasswords(cfg)
-				}
-				if enforceAll && customCharset == "" {
-					for i, pw := range passwords {
-						for {
-							valid := true
-							if includeUpper && !HasChar(pw, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
-								valid = false
-							}
-							if includeLower && !HasChar(pw, "abcdefghijklmnopqrstuvwxyz") {
-								valid = false
-							}
-							if includeDigits && !HasChar(pw, "0123456789") {
-								valid = false
-							}
-							if includeSpecials && !HasChar(pw, "!@#$%^&*()-_=+[]{}|;:,.<>/?") {
-								valid = false
-							}
-							if valid {
-								break
-							}
-							pw = generator.GeneratePasswords(cfg)[0]
-							passwords[i] = pw
-						}
-					}
+					fmt.Fprintf(os.Stdout, "[+] %d entries valid; would generate %d secrets\n", entries, secrets)
 				}
+				return
 			}
 		}
 
-		// Output results in requested format
-		if format == "json" {
-			enc := json.NewEncoder(os.Stdout)
-			enc.SetIndent("", "  ")
-			_ = enc.Encode(passwords)
-		} else if format == "table" {
-			fmt.Printf("%-30s %-12s %-8s\n", "Password", "Strength", "Entropy")
-			for _, pwd := range passwords {
-				strength, entropy, _ := generator.CheckPasswordStrength(pwd)
-				fmt.Printf("%-30s %-12s %-8.2f\n", pwd, strength, entropy)
-			}
-		} else if format == "csv" {
-			fmt.Println("Password,Strength,Entropy")
-			for _, pwd := range passwords {
-				strength, entropy, _ := generator.CheckPasswordStrength(pwd)
-				fmt.Printf("%s,%s,%.2f\n", pwd, strength, entropy)
+		sw, err := newSecretWriter(os.Stdout, format, outputFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		sw.verbose, sw.hashAlg, sw.hashOnly, sw.banned = verbose, hashAlg, hashOnly, banned
+		sw.checkPol, sw.checkPreset, sw.ctx = checkPol, checkPreset, cmd.Context()
+		if err := sw.begin(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+		pool := newGeneratePool(cmd.Context(), workers, sw, hashAlg)
+		// stop writes everything generated so far, then reports err.
+		stop := func(format string, err error) {
+			_ = pool.close()
+			_ = sw.flush()
+			fmt.Fprintf(os.Stderr, format, err)
+			os.Exit(1)
+		}
+		// emit queues the secrets of one configuration, from --input line
+		// line or 0. Workers generate them in parallel and they are
+		// written in the order queued.
+		emit := func(c generateEntry, line int) {
+			if err := pool.submit(c, line); err != nil {
+				stop("Error: %v\n", err)
 			}
-		} else {
-			for _, pwd := range passwords {
-				if verbose {
-					strength, entropy, suggestions := generator.CheckPasswordStrength(pwd)
-					fmt.Printf("[+] Password: %s\t| Strength: %s | Entropy: %.2f\n", pwd, strength, entropy)
-					if len(suggestions) > 0 {
-						fmt.Println("  Suggestions:")
-						for _, s := range suggestions {
-							fmt.Printf("    - %s\n", s)
-						}
-					}
-				} else {
-					fmt.Println(pwd)
+		}
+		if inputFile != "" {
+			err := readBatch(inputFile, inputFormat, func(l *batchLine, e batch.Entry) error {
+				emit(resolve(l), e.Line)
+				return nil
+			}, func(p batchProblem) error {
+				switch {
+				case strict:
+					// Only if the file changed since it was checked.
+					return p.Error
+				case p.unknownKey:
+					fmt.Fprintf(os.Stderr, "[!] %v (ignored)\n", p.Error)
+				default:
+					fmt.Fprintf(os.Stderr, "[!] %v; skipping entry\n", p.Error)
 				}
+				return nil
+			})
+			if err != nil {
+				stop("Error reading input file: %v\n", err)
 			}
+		} else {
+			emit(resolve(nil), 0)
 		}
 
+		if err := pool.close(); err != nil {
+			stop("Error: %v\n", err)
+		}
+		if err := sw.close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 		if outputFile != "" {
-			err := generator.SavePasswordsToFile(passwords, outputFile)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error saving passwords to file: %v\n", err)
+			fmt.Fprintf(os.Stdout, "[+] Saved %d passwords to %s\n", sw.count, outputFile)
+		}
+		if copyClip && sw.count > 0 {
+			if err := copySecret(sw.first, clipTimeout); err != nil {
+				fmt.Fprintf(os.Stderr, "Error copying to clipboard: %v\n", err)
 				os.Exit(1)
 			}
-			fmt.Fprintf(os.Stdout, "[+] Saved %d passwords to %s\n", len(passwords), outputFile)
-		}
-
-		if copyClip && len(passwords) > 0 {
-			fmt.Println("[Clipboard integration is currently unavailable due to Go import issues]")
 		}
 	},
 }
@@ -295,80 +268,66 @@ func init() {
 	generateCmd.Flags().BoolP("digits", "d", true, "Include digits")
 	generateCmd.Flags().BoolP("specials", "s", true, "Include special characters")
 	generateCmd.Flags().Bool("exclude-similar", false, "Exclude similar/confusing characters (e.g., l, 1, O, 0)")
+	generateCmd.Flags().StringSlice("exclude-profile", nil, "Exclude character profiles: "+strings.Join(pwdforge.ExclusionProfileNames(), ", "))
 	generateCmd.Flags().StringP("output", "o", "", "Save passwords to a file")
 	generateCmd.Flags().BoolP("verbose", "v", false, "Show detailed output (strength, etc.)")
-	generateCmd.Flags().String("format", "plain", "Output format: plain, json, csv, table")
-	generateCmd.Flags().String("custom-charset", "", "Custom character set for password generation")
+	generateCmd.Flags().String("format", "plain", "Output format: plain, json, jsonl, csv, table")
+	generateCmd.Flags().String("custom-charset", "", "Custom character set, e.g. 'a-z0-9' or '\\p{Greek}' (Unicode-aware)")
+	generateCmd.Flags().String("exclude-chars", "", "Characters to remove from the charset (same syntax as --custom-charset)")
 	generateCmd.Flags().Bool("passphrase", false, "Generate passphrase using wordlist")
+	generateCmd.Flags().String("type", "password", "Secret type: password, "+strings.Join(pwdforge.TokenTypes(), ", "))
+	generateCmd.Flags().Int("bytes", 32, "Random bytes for --type hex, base64, base64url, base32 and apikey")
+	generateCmd.Flags().String("key-prefix", pwdforge.DefaultKeyPrefix, "Prefix for --type apikey")
+	generateCmd.Flags().String("hash", "", "Also emit a hash of each password: "+strings.Join(pwdforge.HashAlgorithms(), ", "))
+	generateCmd.Flags().Bool("hash-only", false, "Emit only the hash, not the password (with --hash)")
+	generateCmd.Flags().String("target", "", "Make secrets safe for a context: "+strings.Join(pwdforge.TargetNames(), ", "))
+	generateCmd.Flags().String("pattern", "", "Generate from a template, e.g. 'LLLL-DDDD-SSSS' or 'Cvccvc99!' (see README)")
+	generateCmd.Flags().Bool("pin", false, "Generate numeric PINs, rejecting sequences, repeats, dates and common PINs")
+	generateCmd.Flags().StringSlice("pin-allow", nil, "Weak PIN patterns to allow: sequences, repeats, dates, common")
+	generateCmd.Flags().Bool("pronounceable", false, "Generate pronounceable, syllable-based passwords (easy to read over the phone)")
 	generateCmd.Flags().Bool("enforce-all", false, "Enforce at least one of each selected character type")
-	generateCmd.Flags().String("input", "", "Read password generation parameters from a file (JSON/YAML)")
-	generateCmd.Flags().String("config", "", "Path to config file for default options")
+	generateCmd.Flags().String("input", "", "Read password generation parameters from a file (JSON/YAML lines, JSON array, YAML documents or CSV)")
+	generateCmd.Flags().String("input-format", batch.Auto, "Format of --input: "+strings.Join(batch.Formats, ", ")+" (auto goes by the file extension)")
+	generateCmd.Flags().Bool("strict", false, "Check the whole --input file first; unknown keys are errors instead of warnings")
+	generateCmd.Flags().Bool("dry-run", false, "Only validate the options and --input file, reporting every problem; generate nothing")
+	generateCmd.Flags().String("policy", "", "Only emit passwords that satisfy the policy section of this file")
+	generateCmd.Flags().String("preset", "", "Only emit passwords that satisfy a built-in preset ("+strings.Join(pwdforge.PresetNames(), ", ")+") or an AD secedit INF/LDIF export")
+	generateCmd.Flags().StringSlice("banned-words", nil, "Reject passwords containing a word from this file (one per line, l33t-aware)")
+	generateCmd.Flags().StringToString("context", nil, "Reject passwords containing these values, e.g. user=alice,service=gitlab")
+	generateCmd.Flags().String("check-preset", "", "With --verbose, report each password's compliance with a preset or AD export")
 	generateCmd.Flags().Bool("clipboard", false, "Copy first password to clipboard")
 	generateCmd.Flags().Int("word-count", 4, "Number of words in passphrase (for --passphrase)")
+	generateCmd.Flags().Duration("clipboard-timeout", defaultClipboardTimeout, "Clear the clipboard after this long if it still holds the password (0 to keep)")
+	generateCmd.Flags().Int("workers", 1, "Goroutines generating (and hashing) in parallel; 0 uses one per CPU. Output order does not change")
+	generateDefaults = generateFlagLayer(generateCmd.Flags(), true)
 	RootCmd.AddCommand(generateCmd)
 }
 
-// Helper for random int
-func RandomInt(n int) int {
-	return rand.Intn(n)
-}
-
-// Helper for HasChar
-func HasChar(password, charSet string) bool {
-	return strings.ContainsAny(password, charSet)
-}
-
-// Config struct for YAML/JSON config
-// Only fields relevant to password generation
-
-type GenerateConfig struct {
-	Length          int    `yaml:"length" json:"length"`
-	Count           int    `yaml:"count" json:"count"`
-	IncludeUpper    bool   `yaml:"include_upper" json:"include_upper"`
-	IncludeLower    bool   `yaml:"include_lower" json:"include_lower"`
-	IncludeDigits   bool   `yaml:"include_digits" json:"include_digits"`
-	IncludeSpecials bool   `yaml:"include_specials" json:"include_specials"`
-	ExcludeSimilar  bool   `yaml:"exclude_similar" json:"exclude_similar"`
-	CustomCharset   string `yaml:"custom_charset" json:"custom_charset"`
-	EnforceAll      bool   `yaml:"enforce_all" json:"enforce_all"`
-	Passphrase      bool   `yaml:"passphrase" json:"passphrase"`
-	WordCount       int    `yaml:"word_count" json:"word_count"`
-}
-
-// Helper to load config from YAML or JSON
-func loadGenerateConfig(path string) (*GenerateConfig, error) {
-	f, err := os.Open(path)
+// generateFromOptions runs the library generator for opts until ctx is
+// done. The returned map holds the exact entropy of passwords whose mode
+// knows it, keyed by index.
+func generateFromOptions(ctx context.Context, opts pwdforge.Options) ([]string, map[int]float64, error) {
+	secrets, err := pwdforge.Generate(ctx, opts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer f.Close()
-	dec := yaml.NewDecoder(f)
-	var cfg GenerateConfig
-	if err := dec.Decode(&cfg); err != nil {
-		return nil, err
+	passwords := make([]string, len(secrets))
+	entropies := map[int]float64{}
+	for i, s := range secrets {
+		passwords[i] = s.Value
+		if s.Entropy > 0 {
+			entropies[i] = s.Entropy
+		}
 	}
-	return &cfg, nil
+	return passwords, entropies, nil
 }
 
-// Built-in wordlist for passphrase generation (short, for demo)
-var defaultWordlist = []string{
-	"apple", "banana", "cat", "dog", "elephant", "fish", "grape", "hat", "ice", "jungle",
-	"kite", "lemon", "monkey", "nest", "orange", "pear", "queen", "rose", "sun", "tree",
-	"umbrella", "violet", "wolf", "xray", "yak", "zebra",
-}
-
-// GeneratePassphrase creates a passphrase of n words from the wordlist
-func GeneratePassphrase(wordCount int, wordlist []string) string {
-	if wordCount <= 0 {
-		wordCount = 4
-	}
-	if len(wordlist) == 0 {
-		wordlist = defaultWordlist
-	}
-	words := make([]string, wordCount)
-	for i := 0; i < wordCount; i++ {
-		idx := RandomInt(len(wordlist))
-		words[i] = wordlist[idx]
+// checkStrength rates pwd, preferring the generator's exact entropy over the
+// character-pool estimate when one is known for index i.
+func checkStrength(pwd string, knownEntropy map[int]float64, i int, banned *pwdforge.Blocklist) (string, float64, []string) {
+	s := pwdforge.CheckStrength(pwd, banned)
+	if e, ok := knownEntropy[i]; ok {
+		s.Entropy = e
 	}
-	return strings.Join(words, "-")
+	return s.Rating, s.Entropy, s.Suggestions
 }
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+
+	"pwdforge/internal/batch"
+	"pwdforge/pkg/pwdforge"
+)
+
+// batchLine is the schema of an --input entry: a layer of generation
+// options plus the label and username copied to json and jsonl records.
+// Settings of the whole run, such as hash and workers, belong in --config
+// and are unknown keys here.
+type batchLine struct {
+	GenerateOptions `yaml:",inline"`
+	Label           *string `yaml:"label"`
+	Username        *string `yaml:"username"`
+}
+
+// batchProblem is something wrong with an --input entry. Unknown keys are
+// ignored unless --strict; other problems make the entry unusable.
+type batchProblem struct {
+	*batch.Error
+	unknownKey bool
+}
+
+// readBatch calls fn with every entry of the --input file at path that
+// decodes. Problems are passed to report, which returns an error to stop
+// reading; entries that do not parse or hold values of the wrong type are
+// then skipped. Errors from fn stop reading and are returned as is.
+func readBatch(path, format string, fn func(*batchLine, batch.Entry) error, report func(batchProblem) error) error {
+	return batch.Read(path, format, func(e batch.Entry) error {
+		var l batchLine
+		unknown, err := e.Decode(&l)
+		for _, u := range unknown {
+			if err := report(batchProblem{Error: u, unknownKey: true}); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			var berr *batch.Error
+			if !errors.As(err, &berr) {
+				return err
+			}
+			return report(batchProblem{Error: berr})
+		}
+		return fn(&l, e)
+	}, func(err *batch.Error) error {
+		return report(batchProblem{Error: err})
+	})
+}
+
+// entryError locates err, returned for the options of entry e, at the key
+// it is about.
+func entryError(e batch.Entry, err error) *batch.Error {
+	line, column := e.Line, e.Column
+	var oerr *pwdforge.OptionError
+	if errors.As(err, &oerr) {
+		line, column = e.Pos(oerr.Option)
+	}
+	return &batch.Error{Line: line, Column: column, Err: err}
+}
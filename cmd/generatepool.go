@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+
+	"pwdforge/pkg/pwdforge"
+)
+
+// generateChunk is how many secrets one job generates. Large counts are
+// split so that every worker gets a share, and small enough that the
+// chunks waiting to be written stay small.
+const generateChunk = 1024
+
+// generatePool generates secrets on several goroutines and writes them in
+// the order the configurations were submitted, so --workers changes only
+// the speed, never the order of the output relative to the --input file.
+type generatePool struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	sw     *secretWriter
+	// hashAlg is hashed by the workers, since hashing usually costs far
+	// more than generating.
+	hashAlg string
+
+	jobs chan generateJob
+	// ordered receives each job's result channel in submission order.
+	ordered chan chan generateResult
+	workers sync.WaitGroup
+	written chan struct{}
+	closed  sync.Once
+	// err is the first generation or write error; set by the writer
+	// goroutine and read after written is closed or by submit.
+	mu  sync.Mutex
+	err error
+}
+
+// generateEntry is one configuration to generate from, with every layer
+// applied, and the label of its --input entry. The entry's username is
+// Options.Username.
+type generateEntry struct {
+	pwdforge.Options
+	label string
+}
+
+type generateJob struct {
+	opts   pwdforge.Options
+	src    *batchSource
+	result chan generateResult
+}
+
+type generateResult struct {
+	secrets []pwdforge.Secret
+	hashes  []string
+	src     *batchSource
+	err     error
+}
+
+// newGeneratePool starts workers goroutines, each drawing from its own
+// buffered crypto/rand stream, and a writer that feeds sw.
+func newGeneratePool(ctx context.Context, workers int, sw *secretWriter, hashAlg string) *generatePool {
+	ctx, cancel := context.WithCancel(ctx)
+	p := &generatePool{
+		ctx:     ctx,
+		cancel:  cancel,
+		sw:      sw,
+		hashAlg: hashAlg,
+		jobs:    make(chan generateJob),
+		ordered: make(chan chan generateResult, 4*workers),
+		written: make(chan struct{}),
+	}
+	p.workers.Add(workers)
+	for range workers {
+		go p.work()
+	}
+	go p.write()
+	return p
+}
+
+func (p *generatePool) work() {
+	defer p.workers.Done()
+	random := bufio.NewReaderSize(rand.Reader, 4096)
+	for job := range p.jobs {
+		job.opts.Rand = random
+		r := generateResult{src: job.src}
+		r.secrets, r.err = pwdforge.Generate(p.ctx, job.opts)
+		if r.err == nil && p.hashAlg != "" {
+			r.hashes = make([]string, len(r.secrets))
+			for i, s := range r.secrets {
+				if r.hashes[i], r.err = pwdforge.Hash(p.hashAlg, s.Value); r.err != nil {
+					r.err = fmt.Errorf("hashing password: %w", r.err)
+					break
+				}
+			}
+		}
+		job.result <- r
+	}
+}
+
+// write outputs results in submission order. After the first error it
+// only drains the remaining results so that submit never blocks.
+func (p *generatePool) write() {
+	defer close(p.written)
+	for result := range p.ordered {
+		r := <-result
+		if p.failed() != nil {
+			continue
+		}
+		err := r.err
+		if err != nil && r.src.line > 0 {
+			err = fmt.Errorf("line %d: %w", r.src.line, err)
+		}
+		for i := 0; err == nil && i < len(r.secrets); i++ {
+			var hash string
+			if r.hashes != nil {
+				hash = r.hashes[i]
+			}
+			err = p.sw.write(r.secrets[i].Value, r.secrets[i].Entropy, hash, r.src)
+		}
+		if err != nil {
+			p.fail(err)
+		}
+	}
+}
+
+func (p *generatePool) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil {
+		p.err = err
+		p.cancel()
+	}
+}
+
+func (p *generatePool) failed() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// submit queues c.Count secrets from c, read from --input line line (0
+// for none), split into chunks. It blocks while the workers are busy and
+// returns the first error once generation or writing has failed.
+func (p *generatePool) submit(c generateEntry, line int) error {
+	src, err := p.sw.source(c, line)
+	if err != nil {
+		if line > 0 {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		return err
+	}
+	// Zero means the library default of one; negative counts go through
+	// unchanged so that the library rejects them.
+	remaining := c.Count
+	if remaining == 0 {
+		remaining = 1
+	}
+	for {
+		if err := p.failed(); err != nil {
+			return err
+		}
+		opts := c.Options
+		opts.Count = min(remaining, generateChunk)
+		remaining -= opts.Count
+		job := generateJob{opts: opts, src: src, result: make(chan generateResult, 1)}
+		select {
+		case p.ordered <- job.result:
+		case <-p.ctx.Done():
+			return p.stopped()
+		}
+		// The writer is waiting for this result now, so the job must run
+		// even if the pool is failing; workers fail fast on the cancelled
+		// context.
+		p.jobs <- job
+		if remaining <= 0 {
+			return nil
+		}
+	}
+}
+
+// stopped returns the error that stopped the pool, or the context's.
+func (p *generatePool) stopped() error {
+	if err := p.failed(); err != nil {
+		return err
+	}
+	return p.ctx.Err()
+}
+
+// close waits for every submitted secret to be written and returns the
+// first error. Later calls only return the error.
+func (p *generatePool) close() error {
+	p.closed.Do(func() {
+		close(p.jobs)
+		p.workers.Wait()
+		close(p.ordered)
+		<-p.written
+		p.cancel()
+	})
+	return p.failed()
+}
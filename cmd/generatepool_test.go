@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"pwdforge/pkg/pwdforge"
+)
+
+// BenchmarkGeneratePool measures generate --workers throughput for 16- and
+// 64-character passwords, written as plain lines and discarded. Each
+// iteration is one password.
+func BenchmarkGeneratePool(b *testing.B) {
+	for _, length := range []int{16, 64} {
+		for _, workers := range []int{1, 2, 4, 8} {
+			b.Run(fmt.Sprintf("length=%d/workers=%d", length, workers), func(b *testing.B) {
+				opts := pwdforge.DefaultOptions()
+				opts.Length, opts.Count = length, b.N
+				benchGeneratePool(b, workers, generateEntry{Options: opts}, "")
+			})
+		}
+	}
+}
+
+// BenchmarkGeneratePoolHashed measures how workers spread the hashing cost
+// of generate --hash.
+func BenchmarkGeneratePoolHashed(b *testing.B) {
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			opts := pwdforge.DefaultOptions()
+			opts.Count = b.N
+			benchGeneratePool(b, workers, generateEntry{Options: opts}, "sha512crypt")
+		})
+	}
+}
+
+func benchGeneratePool(b *testing.B, workers int, c generateEntry, hashAlg string) {
+	sw, err := newSecretWriter(io.Discard, "plain", "")
+	if err != nil {
+		b.Fatal(err)
+	}
+	sw.hashAlg = hashAlg
+	if err := sw.begin(); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	pool := newGeneratePool(context.Background(), workers, sw, hashAlg)
+	if err := pool.submit(c, 0); err != nil {
+		b.Fatal(err)
+	}
+	if err := pool.close(); err != nil {
+		b.Fatal(err)
+	}
+	if err := sw.close(); err != nil {
+		b.Fatal(err)
+	}
+}
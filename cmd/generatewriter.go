@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"pwdforge/pkg/pwdforge"
+)
+
+// secretWriter writes generated secrets as they are produced, so generate
+// never holds a whole batch in memory. Output is buffered; close flushes
+// it and finishes formats such as json that need a closing bracket.
+type secretWriter struct {
+	out     *bufio.Writer
+	csv     *csv.Writer
+	jsonl   *json.Encoder
+	format  string
+	verbose bool
+	// hashAlg, when set, adds a hash column; hashOnly omits the secret.
+	hashAlg  string
+	hashOnly bool
+	// banned marks matching passwords Weak in strength columns.
+	banned *pwdforge.Blocklist
+	// checkPol, when set, reports preset compliance in verbose output.
+	// Breach lookups for it stop when ctx is done.
+	checkPol    *pwdforge.Policy
+	checkPreset string
+	ctx         context.Context
+
+	// file receives the plain lines written to --output.
+	file    *os.File
+	fileBuf *bufio.Writer
+
+	count int
+	// first is the first rendered secret, for --clipboard.
+	first string
+}
+
+// writerBufferSize is large enough that writing millions of short lines
+// costs few system calls.
+const writerBufferSize = 64 << 10
+
+// newSecretWriter starts output in format to w. When outputFile is set the
+// plain lines are also written there.
+func newSecretWriter(w io.Writer, format, outputFile string) (*secretWriter, error) {
+	switch format {
+	case "plain", "json", "jsonl", "csv", "table":
+	default:
+		return nil, fmt.Errorf("unknown format %q (want plain, json, jsonl, csv or table)", format)
+	}
+	sw := &secretWriter{out: bufio.NewWriterSize(w, writerBufferSize), format: format, ctx: context.Background()}
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return nil, err
+		}
+		sw.file, sw.fileBuf = f, bufio.NewWriterSize(f, writerBufferSize)
+	}
+	return sw, nil
+}
+
+// begin writes the header of formats that have one. It must be called
+// once, after the options are set and before the first write.
+func (sw *secretWriter) begin() error {
+	hashed := sw.hashAlg != ""
+	switch sw.format {
+	case "json":
+		_, err := sw.out.WriteString("[")
+		return err
+	case "jsonl":
+		sw.jsonl = json.NewEncoder(sw.out)
+		// Fixtures are read by programs, not embedded in HTML.
+		sw.jsonl.SetEscapeHTML(false)
+	case "table":
+		var err error
+		switch {
+		case !hashed:
+			_, err = fmt.Fprintf(sw.out, "%-30s %-12s %-8s\n", "Password", "Strength", "Entropy")
+		case sw.hashOnly:
+			_, err = fmt.Fprintf(sw.out, "%-12s %-8s %s\n", "Strength", "Entropy", "Hash")
+		default:
+			_, err = fmt.Fprintf(sw.out, "%-30s %-12s %-8s %s\n", "Password", "Strength", "Entropy", "Hash")
+		}
+		return err
+	case "csv":
+		sw.csv = csv.NewWriter(sw.out)
+		header := []string{"Password", "Strength", "Entropy"}
+		if sw.hashOnly {
+			header = header[1:]
+		}
+		if hashed {
+			header = append(header, "Hash")
+		}
+		return sw.csv.Write(header)
+	}
+	return nil
+}
+
+// batchSource describes the configuration a batch of secrets came from.
+type batchSource struct {
+	// target renders secrets for plain output, --output and the clipboard.
+	target string
+	// line is the --input line number, or 0 without --input.
+	line     int
+	label    string
+	username string
+	mode     string
+	// config is the resolved generation options as JSON; only json and
+	// jsonl records need it.
+	config json.RawMessage
+}
+
+// source describes c, read from --input line line (0 for none).
+func (sw *secretWriter) source(c generateEntry, line int) (*batchSource, error) {
+	if _, err := pwdforge.Render(c.Target, ""); err != nil {
+		return nil, err
+	}
+	src := &batchSource{target: c.Target, line: line, label: c.label, username: c.Username, mode: c.Mode()}
+	if sw.format == "json" || sw.format == "jsonl" {
+		resolved, err := c.Options.Resolved()
+		if err != nil {
+			return nil, err
+		}
+		if src.config, err = json.Marshal(resolved); err != nil {
+			return nil, err
+		}
+	}
+	return src, nil
+}
+
+// secretRecord is one --format json or jsonl record.
+type secretRecord struct {
+	// Line is the --input line the secret was generated for.
+	Line     int    `json:"line,omitempty"`
+	Label    string `json:"label,omitempty"`
+	Username string `json:"username,omitempty"`
+	// Password is omitted with --hash-only.
+	Password string  `json:"password,omitempty"`
+	Hash     string  `json:"hash,omitempty"`
+	Mode     string  `json:"mode"`
+	Entropy  float64 `json:"entropy"`
+	Strength string  `json:"strength"`
+	// Config is the resolved generation options.
+	Config json.RawMessage `json:"config"`
+}
+
+// write outputs one secret. entropy is the generator's exact entropy, or 0
+// to estimate it; hash is the secret's hash when hashAlg is set.
+func (sw *secretWriter) write(pwd string, entropy float64, hash string, src *batchSource) error {
+	// Checked by source.
+	rendered, _ := pwdforge.Render(src.target, pwd)
+	if sw.count == 0 {
+		sw.first = rendered
+	}
+	sw.count++
+
+	// plain is the secret as written by plain output and --output: the
+	// rendered secret, its hash, or both separated by a tab.
+	plain := rendered
+	switch {
+	case hash != "" && sw.hashOnly:
+		plain = hash
+	case hash != "":
+		plain = rendered + "\t" + hash
+	}
+	if sw.fileBuf != nil {
+		if _, err := sw.fileBuf.WriteString(plain + "\n"); err != nil {
+			return err
+		}
+	}
+
+	rate := func() (string, float64, []string) {
+		s := pwdforge.CheckStrength(pwd, sw.banned)
+		if entropy > 0 {
+			s.Entropy = entropy
+		}
+		return s.Rating, s.Entropy, s.Suggestions
+	}
+	switch sw.format {
+	case "json", "jsonl":
+		strength, entropy, _ := rate()
+		v := secretRecord{
+			Line:     src.line,
+			Label:    src.label,
+			Username: src.username,
+			Password: pwd,
+			Hash:     hash,
+			Mode:     src.mode,
+			Entropy:  entropy,
+			Strength: strength,
+			Config:   src.config,
+		}
+		if sw.hashOnly {
+			v.Password = ""
+		}
+		if sw.format == "jsonl" {
+			return sw.jsonl.Encode(v)
+		}
+		b, err := json.MarshalIndent(v, "  ", "  ")
+		if err != nil {
+			return err
+		}
+		sep := ",\n  "
+		if sw.count == 1 {
+			sep = "\n  "
+		}
+		if _, err := sw.out.WriteString(sep); err != nil {
+			return err
+		}
+		_, err = sw.out.Write(b)
+		return err
+	case "table":
+		strength, entropy, _ := rate()
+		var err error
+		switch {
+		case hash == "":
+			_, err = fmt.Fprintf(sw.out, "%-30s %-12s %-8.2f\n", pwd, strength, entropy)
+		case sw.hashOnly:
+			_, err = fmt.Fprintf(sw.out, "%-12s %-8.2f %s\n", strength, entropy, hash)
+		default:
+			_, err = fmt.Fprintf(sw.out, "%-30s %-12s %-8.2f %s\n", pwd, strength, entropy, hash)
+		}
+		return err
+	case "csv":
+		strength, entropy, _ := rate()
+		row := []string{pwd, strength, strconv.FormatFloat(entropy, 'f', 2, 64)}
+		if sw.hashOnly {
+			row = row[1:]
+		}
+		if hash != "" {
+			row = append(row, hash)
+		}
+		return sw.csv.Write(row)
+	}
+
+	if !sw.verbose {
+		_, err := sw.out.WriteString(plain + "\n")
+		return err
+	}
+	strength, rated, suggestions := rate()
+	switch {
+	case hash == "":
+		fmt.Fprintf(sw.out, "[+] Password: %s\t| Strength: %s | Entropy: %.2f\n", pwd, strength, rated)
+	case sw.hashOnly:
+		fmt.Fprintf(sw.out, "[+] Hash: %s\t| Strength: %s | Entropy: %.2f\n", hash, strength, rated)
+	default:
+		fmt.Fprintf(sw.out, "[+] Password: %s\t| Strength: %s | Entropy: %.2f | Hash: %s\n", pwd, strength, rated, hash)
+	}
+	if len(suggestions) > 0 {
+		fmt.Fprintln(sw.out, "  Suggestions:")
+		for _, s := range suggestions {
+			fmt.Fprintf(sw.out, "    - %s\n", s)
+		}
+	}
+	if sw.checkPol != nil {
+		results, err := pwdforge.CheckPolicy(sw.ctx, sw.checkPol, pwd, pwdforge.PolicyOptions{Entropy: entropy})
+		if err != nil {
+			return fmt.Errorf("checking preset: %w", err)
+		}
+		if pwdforge.Passed(results) {
+			fmt.Fprintf(sw.out, "  Preset %s: compliant\n", sw.checkPreset)
+		} else {
+			fmt.Fprintf(sw.out, "  Preset %s: NOT compliant\n", sw.checkPreset)
+			for _, r := range results {
+				if !r.Passed {
+					fmt.Fprintf(sw.out, "    - %s: %s\n", r.Rule, r.Detail)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// flush writes buffered output without finishing it, so that stdout is
+// complete up to the last secret before an error is reported.
+func (sw *secretWriter) flush() error {
+	if sw.csv != nil {
+		sw.csv.Flush()
+	}
+	if sw.fileBuf != nil {
+		if err := sw.fileBuf.Flush(); err != nil {
+			return err
+		}
+	}
+	return sw.out.Flush()
+}
+
+// close finishes the output and the --output file.
+func (sw *secretWriter) close() error {
+	if sw.format == "json" {
+		end := "\n]\n"
+		if sw.count == 0 {
+			end = "]\n"
+		}
+		if _, err := sw.out.WriteString(end); err != nil {
+			return err
+		}
+	}
+	if sw.csv != nil {
+		sw.csv.Flush()
+		if err := sw.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if err := sw.out.Flush(); err != nil {
+		return err
+	}
+	if sw.file == nil {
+		return nil
+	}
+	if err := sw.fileBuf.Flush(); err != nil {
+		sw.file.Close()
+		return fmt.Errorf("saving passwords to file: %w", err)
+	}
+	if err := sw.file.Close(); err != nil {
+		return fmt.Errorf("saving passwords to file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"pwdforge/pkg/pwdforge"
+
+	"github.com/spf13/cobra"
+)
+
+var hashCmd = &cobra.Command{
+	Use:   "hash",
+	Short: "Hash a password read from a hidden prompt",
+	Long: `Reads a password without echoing it (or from stdin when piped) and prints
+its hash in the chosen format, ready for htpasswd, /etc/shadow, application
+configs or PostgreSQL.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		alg, _ := cmd.Flags().GetString("algorithm")
+		password, err := readNewSecret("Password: ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading password: %v\n", err)
+			os.Exit(1)
+		}
+		if len(password) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: Password cannot be empty.")
+			os.Exit(1)
+		}
+		h, err := pwdforge.Hash(alg, string(password))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error hashing password: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(h)
+	},
+}
+
+func init() {
+	hashCmd.Flags().StringP("algorithm", "a", "bcrypt", "Hash algorithm: "+strings.Join(pwdforge.HashAlgorithms(), ", "))
+	RootCmd.AddCommand(hashCmd)
+}
@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
-	"pwdforge/internal/generator"
-	"pwdforge/internal/pwnchecker"
 	"strings"
+	"time"
+
+	"pwdforge/pkg/pwdforge"
 
 	"github.com/spf13/cobra"
 )
@@ -15,6 +17,7 @@ var interactiveCmd = &cobra.Command{
 	Use:   "interactive",
 	Short: "Interactive mode for password generation and checking",
 	Run: func(cmd *cobra.Command, args []string) {
+		clipTimeout, _ := cmd.Flags().GetDuration("clipboard-timeout")
 		reader := bufio.NewReader(os.Stdin)
 		fmt.Println("Welcome to PwdForge Interactive Mode!")
 		for {
@@ -27,7 +30,7 @@ var interactiveCmd = &cobra.Command{
 			choice = strings.TrimSpace(choice)
 			switch choice {
 			case "1":
-				interactiveGenerate(reader)
+				interactiveGenerate(reader, clipTimeout)
 			case "2":
 				interactiveCheck(reader)
 			case "3":
@@ -40,7 +43,7 @@ var interactiveCmd = &cobra.Command{
 	},
 }
 
-func interactiveGenerate(reader *bufio.Reader) {
+func interactiveGenerate(reader *bufio.Reader, clipTimeout time.Duration) {
 	fmt.Print("Password length (default 12): ")
 	lengthStr, _ := reader.ReadString('\n')
 	lengthStr = strings.TrimSpace(lengthStr)
@@ -70,11 +73,19 @@ func interactiveGenerate(reader *bufio.Reader) {
 	fmt.Print("Exclude similar/confusing characters? (y/N): ")
 	similarStr, _ := reader.ReadString('\n')
 	excludeSimilar := strings.HasPrefix(strings.ToLower(strings.TrimSpace(similarStr)), "y")
+	fmt.Printf("Exclude characters unsafe for (%s; comma-separated, blank for none): ", strings.Join(pwdforge.ExclusionProfileNames(), ", "))
+	profileStr, _ := reader.ReadString('\n')
+	var profiles []string
+	for _, p := range strings.Split(profileStr, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			profiles = append(profiles, p)
+		}
+	}
 	fmt.Print("Copy to clipboard? (y/N): ")
 	clipStr, _ := reader.ReadString('\n')
 	copyClip := strings.HasPrefix(strings.ToLower(strings.TrimSpace(clipStr)), "y")
 
-	passwords := generator.GeneratePasswords(generator.PasswordConfig{
+	secrets, err := pwdforge.Generate(context.Background(), pwdforge.Options{
 		Length:          length,
 		Count:           count,
 		IncludeUpper:    upper,
@@ -82,23 +93,27 @@ func interactiveGenerate(reader *bufio.Reader) {
 		IncludeDigits:   digit,
 		IncludeSpecials: special,
 		ExcludeSimilar:  excludeSimilar,
+		ExcludeProfiles: profiles,
 	})
-	for _, pwd := range passwords {
-		strength, entropy, suggestions := generator.CheckPasswordStrength(pwd)
-		fmt.Printf("[+] Password: %s\t| Strength: %s | Entropy: %.2f\n", pwd, strength, entropy)
-		if len(suggestions) > 0 {
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, s := range secrets {
+		strength := pwdforge.CheckStrength(s.Value, nil)
+		fmt.Printf("[+] Password: %s\t| Strength: %s | Entropy: %.2f\n", s.Value, strength.Rating, strength.Entropy)
+		if len(strength.Suggestions) > 0 {
 			fmt.Println("  Suggestions:")
-			for _, s := range suggestions {
-				fmt.Printf("    - %s\n", s)
+			for _, suggestion := range strength.Suggestions {
+				fmt.Printf("    - %s\n", suggestion)
 			}
 		}
-		if copyClip {
-			fmt.Println("[Clipboard integration is currently unavailable due to Go import issues]")
-		}
 	}
 	// If copyClip is true, copy the first password to clipboard and notify
-	if copyClip && len(passwords) > 0 {
-		fmt.Println("[Clipboard integration is currently unavailable due to Go import issues]")
+	if copyClip && len(secrets) > 0 {
+		if err := copySecret(secrets[0].Value, clipTimeout); err != nil {
+			fmt.Printf("Clipboard unavailable: %v\n", err)
+		}
 	}
 }
 
@@ -110,7 +125,7 @@ func interactiveCheck(reader *bufio.Reader) {
 		fmt.Println("Password cannot be empty.")
 		return
 	}
-	exposed, count, err := pwnchecker.CheckPasswordPwned(pwd)
+	exposed, count, err := pwdforge.CheckPwned(context.Background(), pwd)
 	if err != nil {
 		fmt.Printf("Error checking password: %v\n", err)
 		return
@@ -123,5 +138,6 @@ func interactiveCheck(reader *bufio.Reader) {
 }
 
 func init() {
+	interactiveCmd.Flags().Duration("clipboard-timeout", defaultClipboardTimeout, "Clear the clipboard after this long if it still holds the password (0 to keep)")
 	RootCmd.AddCommand(interactiveCmd)
 }
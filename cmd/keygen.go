@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"pwdforge/internal/keygen"
+
+	"github.com/spf13/cobra"
+)
+
+var keygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Generate key pairs and symmetric keys",
+	Long: `Generates Ed25519, ECDSA P-256/P-384 and RSA key pairs (PEM or OpenSSH),
+WireGuard keys and JWT HS256/HS512 signing secrets. Private keys are written
+with 0600 permissions; public keys go to <out>.pub.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keyType, _ := cmd.Flags().GetString("type")
+		format, _ := cmd.Flags().GetString("format")
+		bits, _ := cmd.Flags().GetInt("bits")
+		comment, _ := cmd.Flags().GetString("comment")
+		out, _ := cmd.Flags().GetString("out")
+		force, _ := cmd.Flags().GetBool("force")
+		askPassphrase, _ := cmd.Flags().GetBool("ask-passphrase")
+		passphraseFile, _ := cmd.Flags().GetString("passphrase-file")
+
+		var passphrase []byte
+		if askPassphrase && passphraseFile != "" {
+			fmt.Fprintln(os.Stderr, "Error: Only one of --ask-passphrase or --passphrase-file should be provided.")
+			os.Exit(1)
+		}
+		if askPassphrase {
+			var err error
+			passphrase, err = readNewSecret("Key passphrase: ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading passphrase: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if passphraseFile != "" {
+			data, err := os.ReadFile(passphraseFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading passphrase file: %v\n", err)
+				os.Exit(1)
+			}
+			passphrase = []byte(strings.TrimRight(string(data), "\r\n"))
+		}
+
+		pair, err := keygen.Generate(keygen.Options{
+			Type:       keyType,
+			Format:     format,
+			Bits:       bits,
+			Comment:    comment,
+			Passphrase: passphrase,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating key: %v\n", err)
+			os.Exit(1)
+		}
+
+		if out == "" {
+			os.Stdout.Write(pair.Private)
+			os.Stdout.Write(pair.Public)
+			return
+		}
+		if err := writeKeyFile(out, pair.Private, 0600, force); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing private key: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stdout, "[+] Wrote private key to %s\n", out)
+		if len(pair.Public) > 0 {
+			if err := writeKeyFile(out+".pub", pair.Public, 0644, force); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing public key: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stdout, "[+] Wrote public key to %s.pub\n", out)
+		}
+	},
+}
+
+// writeKeyFile creates path with exactly perm, refusing to replace an
+// existing file unless force is set.
+func writeKeyFile(path string, data []byte, perm os.FileMode, force bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, perm)
+	if err != nil {
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func init() {
+	keygenCmd.Flags().StringP("type", "t", "ed25519", "Key type: "+strings.Join(keygen.Types, ", "))
+	keygenCmd.Flags().String("format", "pem", "Key format for signing keys: pem, openssh")
+	keygenCmd.Flags().Int("bits", keygen.DefaultRSABits, "RSA key size in bits")
+	keygenCmd.Flags().StringP("comment", "C", "", "Comment for OpenSSH keys")
+	keygenCmd.Flags().StringP("out", "o", "", "Write the private key to this file and the public key to <file>.pub (default: stdout)")
+	keygenCmd.Flags().Bool("force", false, "Overwrite existing key files")
+	keygenCmd.Flags().Bool("ask-passphrase", false, "Prompt for a passphrase to encrypt the private key")
+	keygenCmd.Flags().String("passphrase-file", "", "Read the private key passphrase from a file")
+	RootCmd.AddCommand(keygenCmd)
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"strings"
+
+	"pwdforge/pkg/pwdforge"
+
+	"github.com/spf13/cobra"
+)
+
+// addPasswordFlags registers the generator options shared by commands that
+// create a single password as a side effect, such as htpasswd and shadow.
+func addPasswordFlags(cmd *cobra.Command) {
+	cmd.Flags().IntP("length", "l", 20, "Length of the generated password")
+	cmd.Flags().BoolP("uppercase", "u", true, "Include uppercase letters")
+	cmd.Flags().BoolP("lowercase", "w", true, "Include lowercase letters")
+	cmd.Flags().BoolP("digits", "d", true, "Include digits")
+	cmd.Flags().BoolP("specials", "s", true, "Include special characters")
+	cmd.Flags().Bool("exclude-similar", false, "Exclude similar/confusing characters (e.g., l, 1, O, 0)")
+	cmd.Flags().StringSlice("exclude-profile", nil, "Exclude character profiles: "+strings.Join(pwdforge.ExclusionProfileNames(), ", "))
+	cmd.Flags().String("exclude-chars", "", "Characters to remove from the charset (same syntax as generate --custom-charset)")
+	cmd.Flags().Bool("enforce-all", true, "Enforce at least one of each selected character type")
+	cmd.Flags().Bool("pronounceable", false, "Generate a pronounceable, syllable-based password")
+	cmd.Flags().String("pattern", "", "Generate from a template, e.g. 'Cvccvc-DDDD' (see README)")
+}
+
+// passwordOptionsFromFlags reads the flags added by addPasswordFlags.
+func passwordOptionsFromFlags(cmd *cobra.Command) pwdforge.Options {
+	c := pwdforge.Options{Count: 1}
+	c.Length, _ = cmd.Flags().GetInt("length")
+	c.IncludeUpper, _ = cmd.Flags().GetBool("uppercase")
+	c.IncludeLower, _ = cmd.Flags().GetBool("lowercase")
+	c.IncludeDigits, _ = cmd.Flags().GetBool("digits")
+	c.IncludeSpecials, _ = cmd.Flags().GetBool("specials")
+	c.ExcludeSimilar, _ = cmd.Flags().GetBool("exclude-similar")
+	c.ExcludeProfiles, _ = cmd.Flags().GetStringSlice("exclude-profile")
+	c.ExcludeChars, _ = cmd.Flags().GetString("exclude-chars")
+	c.EnforceAll, _ = cmd.Flags().GetBool("enforce-all")
+	c.Pronounceable, _ = cmd.Flags().GetBool("pronounceable")
+	c.Pattern, _ = cmd.Flags().GetString("pattern")
+	return c
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"pwdforge/pkg/pwdforge"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Work with organisation password policies",
+	Long: `A policy is the "policy" section of a YAML or JSON config file. The same
+file can be passed to generate --config or generate --policy.`,
+}
+
+var policyCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check passwords against a policy file",
+	Long: `Checks one password (hidden prompt, or stdin when piped) or every line of
+--input against the policy and prints the outcome of each rule. Exits with
+status 1 if any password fails a rule.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		policyFile, _ := cmd.Flags().GetString("policy")
+		preset, _ := cmd.Flags().GetString("preset")
+		username, _ := cmd.Flags().GetString("username")
+		bannedFiles, _ := cmd.Flags().GetStringSlice("banned-words")
+		contextValues, _ := cmd.Flags().GetStringToString("context")
+		inputFile, _ := cmd.Flags().GetString("input")
+		format, _ := cmd.Flags().GetString("format")
+		if policyFile == "" && preset == "" && len(bannedFiles) == 0 && len(contextValues) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: --policy, --preset, --banned-words or --context must be provided.")
+			os.Exit(1)
+		}
+		pol, err := resolvePolicy(policyFile, preset)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading policy: %v\n", err)
+			os.Exit(1)
+		}
+		if len(bannedFiles) > 0 || len(contextValues) > 0 {
+			pol = pwdforge.Strictest(pol, &pwdforge.Policy{BannedWordFiles: bannedFiles})
+			contextWords := pwdforge.NewBlocklist()
+			for _, value := range contextValues {
+				contextWords.AddContext(value)
+			}
+			pol.Blocklist = pwdforge.MergeBlocklists(pol.Blocklist, contextWords)
+		}
+		if username == "" {
+			username = contextValues["user"]
+		}
+		if err := pol.Prepare(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading policy: %v\n", err)
+			os.Exit(1)
+		}
+
+		var passwords []string
+		if inputFile != "" {
+			file, err := os.Open(inputFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error opening input file: %v\n", err)
+				os.Exit(1)
+			}
+			defer file.Close()
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				if pw := strings.TrimRight(scanner.Text(), "\r"); pw != "" {
+					passwords = append(passwords, pw)
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading input file: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			pw, err := readSecret("Password: ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading password: %v\n", err)
+				os.Exit(1)
+			}
+			passwords = append(passwords, string(pw))
+		}
+
+		type report struct {
+			Password string                  `json:"password,omitempty"`
+			Passed   bool                    `json:"passed"`
+			Rules    []pwdforge.PolicyResult `json:"rules"`
+		}
+		ctx := context.Background()
+		var reports []report
+		allPassed := true
+		for _, pw := range passwords {
+			results, err := pwdforge.CheckPolicy(ctx, pol, pw, pwdforge.PolicyOptions{Username: username})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error checking password: %v\n", err)
+				os.Exit(1)
+			}
+			r := report{Passed: pwdforge.Passed(results), Rules: results}
+			// A prompted password is never echoed back.
+			if inputFile != "" {
+				r.Password = pw
+			}
+			allPassed = allPassed && r.Passed
+			reports = append(reports, r)
+		}
+
+		if format == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(reports)
+		} else {
+			for i, r := range reports {
+				if i > 0 {
+					fmt.Println()
+				}
+				if r.Password != "" {
+					fmt.Printf("Password: '%s'\n", r.Password)
+				}
+				for _, res := range r.Rules {
+					mark := "[+]"
+					if !res.Passed {
+						mark = "[!]"
+					}
+					fmt.Printf("%s %-18s %s\n", mark, res.Rule, res.Detail)
+				}
+				if r.Passed {
+					fmt.Println("[+] Password complies with the policy.")
+				} else {
+					fmt.Println("[!] Password does not comply with the policy.")
+				}
+			}
+		}
+		if !allPassed {
+			os.Exit(1)
+		}
+	},
+}
+
+// loadPreset resolves a built-in preset name or an Active Directory export
+// (secedit INF or LDIF file).
+func loadPreset(spec string) (*pwdforge.Policy, error) {
+	if p, err := pwdforge.LookupPreset(spec); err == nil {
+		return p, nil
+	}
+	if _, err := os.Stat(spec); err != nil {
+		return nil, fmt.Errorf("unknown policy preset %q (want one of %s, or a secedit INF or LDIF file)",
+			spec, strings.Join(pwdforge.PresetNames(), ", "))
+	}
+	p, err := pwdforge.ImportADPolicy(spec)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", spec, err)
+	}
+	return p, p.Validate()
+}
+
+// resolvePolicy loads --policy and --preset, combining them when both are
+// given. It returns nil when neither is set.
+func resolvePolicy(policyFile, preset string) (*pwdforge.Policy, error) {
+	var pol *pwdforge.Policy
+	if policyFile != "" {
+		p, err := loadPolicy(policyFile)
+		if err != nil {
+			return nil, err
+		}
+		pol = p
+	}
+	if preset != "" {
+		p, err := loadPreset(preset)
+		if err != nil {
+			return nil, err
+		}
+		pol = pwdforge.Strictest(pol, p)
+	}
+	return pol, nil
+}
+
+// loadPolicy reads the policy section of a config file.
+func loadPolicy(path string) (*pwdforge.Policy, error) {
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Policy == nil {
+		return nil, fmt.Errorf("%s has no policy section", path)
+	}
+	if err := cfg.Policy.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg.Policy, nil
+}
+
+var policyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print a policy, preset or imported AD policy as YAML",
+	Long: `Prints the resolved policy in the config file format, e.g. to turn an Active
+Directory export into a policy file:
+
+  pwdforge policy show --preset secpol.inf > policy.yaml
+
+Without --policy or --preset it lists the built-in presets.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		policyFile, _ := cmd.Flags().GetString("policy")
+		preset, _ := cmd.Flags().GetString("preset")
+		if policyFile == "" && preset == "" {
+			for _, name := range pwdforge.PresetNames() {
+				fmt.Println(name)
+			}
+			return
+		}
+		pol, err := resolvePolicy(policyFile, preset)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading policy: %v\n", err)
+			os.Exit(1)
+		}
+		out, err := yaml.Marshal(struct {
+			Policy *pwdforge.Policy `yaml:"policy"`
+		}{pol})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(out)
+	},
+}
+
+func init() {
+	presetHelp := "Built-in preset (" + strings.Join(pwdforge.PresetNames(), ", ") + ") or AD secedit INF/LDIF export"
+	policyCheckCmd.Flags().String("policy", "", "Config file with a policy section")
+	policyCheckCmd.Flags().String("preset", "", presetHelp)
+	policyCheckCmd.Flags().String("username", "", "Username for the disallow_username rule (default: the context user)")
+	policyCheckCmd.Flags().StringSlice("banned-words", nil, "Also reject words from this file (one per line)")
+	policyCheckCmd.Flags().StringToString("context", nil, "Also reject these values, e.g. user=alice,service=gitlab")
+	policyCheckCmd.Flags().String("input", "", "Read passwords to check from a file (one per line)")
+	policyCheckCmd.Flags().String("format", "plain", "Output format: plain, json")
+	policyShowCmd.Flags().String("policy", "", "Config file with a policy section")
+	policyShowCmd.Flags().String("preset", "", presetHelp)
+	policyCmd.AddCommand(policyCheckCmd, policyShowCmd)
+	RootCmd.AddCommand(policyCmd)
+}
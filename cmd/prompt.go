@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// readSecret prompts on stderr and reads a line without echo when stdin is a
+// terminal, or a plain line when input is piped.
+func readSecret(prompt string) ([]byte, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return nil, err
+		}
+		return []byte(strings.TrimRight(line, "\r\n")), nil
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	return secret, err
+}
+
+// readNewSecret prompts twice and fails if the entries differ.
+func readNewSecret(prompt string) ([]byte, error) {
+	first, err := readSecret(prompt)
+	if err != nil {
+		return nil, err
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return first, nil
+	}
+	second, err := readSecret("Confirm: ")
+	if err != nil {
+		return nil, err
+	}
+	if string(first) != string(second) {
+		return nil, errors.New("entries do not match")
+	}
+	return first, nil
+}
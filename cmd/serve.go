@@ -0,0 +1,434 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"pwdforge/internal/server"
+	"pwdforge/pkg/pwdforge"
+
+	"github.com/spf13/cobra"
+)
+
+// Limits for API requests, so one call cannot tie up the server.
+const (
+	maxAPICount       = 1000
+	maxAPIHashedCount = 20 // hashing is deliberately slow
+	maxAPILength      = 1024
+	maxAPIBytes       = 1024
+	maxAPIWordCount   = 64
+	// Charset specs: custom_charset, exclude_chars and each pattern class.
+	maxAPICharsetSpec    = 256     // characters in the spec
+	maxAPICharsetSize    = 1 << 16 // characters it expands to
+	maxAPIPatternClasses = 32
+)
+
+// tokenEnv holds an API bearer token, as an alternative to --token-file.
+const tokenEnv = "PWDFORGE_API_TOKEN"
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the JSON HTTP API for generation, strength and breach checks",
+	Long: `Serves POST /v1/generate, POST /v1/strength and POST /v1/pwned (plus an
+unauthenticated GET /healthz) so other tools can use PwdForge without
+shelling out. Clients authenticate with a bearer token (--token-file or
+$PWDFORGE_API_TOKEN) or a client certificate (--client-ca, mTLS). Access logs
+are JSON on stderr and never contain request or response bodies. SIGINT and
+SIGTERM drain in-flight requests before exiting.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		addr, _ := cmd.Flags().GetString("addr")
+		tokenFile, _ := cmd.Flags().GetString("token-file")
+		tlsCert, _ := cmd.Flags().GetString("tls-cert")
+		tlsKey, _ := cmd.Flags().GetString("tls-key")
+		clientCA, _ := cmd.Flags().GetString("client-ca")
+		rate, _ := cmd.Flags().GetFloat64("rate")
+		burst, _ := cmd.Flags().GetInt("burst")
+		shutdownTimeout, _ := cmd.Flags().GetDuration("shutdown-timeout")
+		logFormat, _ := cmd.Flags().GetString("log-format")
+
+		var tokens []string
+		if tokenFile != "" {
+			var err error
+			if tokens, err = readTokens(tokenFile); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading token file: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if t := strings.TrimSpace(os.Getenv(tokenEnv)); t != "" {
+			tokens = append(tokens, t)
+		}
+
+		var handler slog.Handler = slog.NewJSONHandler(os.Stderr, nil)
+		if logFormat == "text" {
+			handler = slog.NewTextHandler(os.Stderr, nil)
+		}
+		srv, err := server.New(server.Options{
+			Addr:            addr,
+			Tokens:          tokens,
+			TLSCert:         tlsCert,
+			TLSKey:          tlsKey,
+			ClientCA:        clientCA,
+			Rate:            rate,
+			Burst:           burst,
+			ShutdownTimeout: shutdownTimeout,
+			Logger:          slog.New(handler),
+		}, apiHandler())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := srv.Run(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// readTokens reads one bearer token per line, skipping blanks and comments.
+func readTokens(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var tokens []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if t := strings.TrimSpace(scanner.Text()); t != "" && !strings.HasPrefix(t, "#") {
+			tokens = append(tokens, t)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%s contains no tokens", path)
+	}
+	return tokens, nil
+}
+
+func apiHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/generate", handleGenerate)
+	mux.HandleFunc("POST /v1/strength", handleStrength)
+	mux.HandleFunc("POST /v1/pwned", handlePwned)
+	return mux
+}
+
+type generatedSecret struct {
+	Password string `json:"password,omitempty"`
+	// Rendered is the password escaped for the target, when that changes it.
+	Rendered string  `json:"rendered,omitempty"`
+	Hash     string  `json:"hash,omitempty"`
+	Strength string  `json:"strength"`
+	Entropy  float64 `json:"entropy"`
+}
+
+// requestError marks a problem with the request itself, as opposed to a
+// failure to generate or to reach HaveIBeenPwned. The HTTP and gRPC APIs
+// share the request logic below and map the two kinds to their own codes.
+type requestError struct{ error }
+
+// generateRequest is the body of generate requests, with the keys of
+// config files. Unlike a config layer it is complete on its own:
+// prepareAPIConfig fills in the defaults.
+type generateRequest struct {
+	pwdforge.Options
+	Hash     string `json:"hash"`
+	HashOnly bool   `json:"hash_only"`
+	// ClipboardTimeout and BannedWordFiles are config file keys that are
+	// rejected with an explanation.
+	ClipboardTimeout string   `json:"clipboard_timeout"`
+	BannedWordFiles  []string `json:"banned_word_files"`
+}
+
+// handleGenerate takes a generateRequest and returns the generated
+// secrets.
+func handleGenerate(w http.ResponseWriter, r *http.Request) {
+	var c generateRequest
+	if err := server.DecodeJSON(r, &c); err != nil {
+		server.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	secrets, err := generateSecrets(r.Context(), c)
+	if err != nil {
+		status := http.StatusUnprocessableEntity
+		if errors.As(err, new(requestError)) {
+			status = http.StatusBadRequest
+		}
+		server.WriteError(w, status, err.Error())
+		return
+	}
+	server.WriteJSON(w, http.StatusOK, map[string]any{"passwords": secrets})
+}
+
+// generateSecrets generates the secrets of a request, stopping when ctx is
+// done.
+func generateSecrets(ctx context.Context, c generateRequest) ([]generatedSecret, error) {
+	if err := prepareAPIConfig(&c); err != nil {
+		return nil, requestError{err}
+	}
+	pws, entropies, err := generateFromOptions(ctx, c.Options)
+	if err != nil {
+		return nil, err
+	}
+	secrets := make([]generatedSecret, len(pws))
+	for i, pw := range pws {
+		strength, entropy, _ := checkStrength(pw, entropies, i, nil)
+		secrets[i] = generatedSecret{Password: pw, Strength: strength, Entropy: entropy}
+		// Checked by prepareAPIConfig.
+		if rendered, _ := pwdforge.Render(c.Target, pw); rendered != pw {
+			secrets[i].Rendered = rendered
+		}
+		if c.Hash != "" {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if secrets[i].Hash, err = pwdforge.Hash(c.Hash, pw); err != nil {
+				return nil, err
+			}
+			if c.HashOnly {
+				secrets[i].Password, secrets[i].Rendered = "", ""
+			}
+		}
+	}
+	return secrets, nil
+}
+
+// prepareAPIConfig applies the library defaults to a request and rejects
+// values that make no sense for a server: file paths, clipboard settings,
+// breach lookups per candidate and sizes beyond the API limits.
+func prepareAPIConfig(c *generateRequest) error {
+	switch {
+	case len(c.BannedWordFiles) > 0 || (c.Policy != nil && len(c.Policy.BannedWordFiles) > 0):
+		return errors.New("banned_word_files cannot be used over the API; send banned_words instead")
+	case c.ClipboardTimeout != "":
+		return errors.New("clipboard_timeout cannot be used over the API")
+	case c.Count < 0 || c.Count > maxAPICount:
+		return fmt.Errorf("count must be between 1 and %d", maxAPICount)
+	case c.Hash != "" && c.Count > maxAPIHashedCount:
+		return fmt.Errorf("count must be at most %d when hashing", maxAPIHashedCount)
+	case c.Length < 0 || c.Length > maxAPILength:
+		return fmt.Errorf("length must be between 1 and %d", maxAPILength)
+	case c.Bytes < 0 || c.Bytes > maxAPIBytes:
+		return fmt.Errorf("bytes must be between 1 and %d", maxAPIBytes)
+	case c.WordCount < 0 || c.WordCount > maxAPIWordCount:
+		return fmt.Errorf("word_count must be between 1 and %d", maxAPIWordCount)
+	case c.Hash != "" && !slices.Contains(pwdforge.HashAlgorithms(), c.Hash):
+		return fmt.Errorf("unknown hash algorithm %q (want one of %s)", c.Hash, strings.Join(pwdforge.HashAlgorithms(), ", "))
+	case c.HashOnly && c.Hash == "":
+		return errors.New("hash_only requires hash")
+	}
+	if _, err := pwdforge.Render(c.Target, ""); err != nil {
+		return err
+	}
+	if len(c.PatternClasses) > maxAPIPatternClasses {
+		return fmt.Errorf("pattern_classes may define at most %d classes", maxAPIPatternClasses)
+	}
+	if err := checkAPICharset("custom_charset", c.CustomCharset); err != nil {
+		return err
+	}
+	if err := checkAPICharset("exclude_chars", c.ExcludeChars); err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.PatternClasses)) {
+		if err := checkAPICharset("pattern_classes."+name, c.PatternClasses[name]); err != nil {
+			return err
+		}
+	}
+	if c.Pattern != "" {
+		n, err := pwdforge.PatternLength(c.Pattern, c.PatternClasses)
+		if err != nil {
+			return err
+		}
+		if n > maxAPILength {
+			return fmt.Errorf("pattern must produce at most %d characters", maxAPILength)
+		}
+	}
+	if c.Policy != nil {
+		switch {
+		case c.Policy.MinLength > maxAPILength || c.Policy.MaxLength > maxAPILength:
+			// The policy lengthens passwords to min_length.
+			return fmt.Errorf("policy min_length and max_length must be at most %d", maxAPILength)
+		case c.Policy.NotPwned:
+			// Each secret could take hundreds of breach lookups.
+			return errors.New("policy not_pwned cannot be used over the API; check secrets with /v1/pwned instead")
+		}
+		if err := c.Policy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	resolved, err := c.Options.Resolved()
+	if err != nil {
+		return err
+	}
+	c.Options = resolved
+	// Unlike the library, the API defaults to every class when none is
+	// selected, as the CLI does.
+	if !c.IncludeUpper && !c.IncludeLower && !c.IncludeDigits && !c.IncludeSpecials && c.CustomCharset == "" {
+		d := pwdforge.DefaultOptions()
+		c.IncludeUpper, c.IncludeLower, c.IncludeDigits, c.IncludeSpecials = d.IncludeUpper, d.IncludeLower, d.IncludeDigits, d.IncludeSpecials
+	}
+	return nil
+}
+
+// checkAPICharset checks that the charset spec of the request key stays
+// within the API limits before anything expands it.
+func checkAPICharset(key, spec string) error {
+	if utf8.RuneCountInString(spec) > maxAPICharsetSpec {
+		return fmt.Errorf("%s must be at most %d characters", key, maxAPICharsetSpec)
+	}
+	n, err := pwdforge.CharsetSize(spec)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	if n > maxAPICharsetSize {
+		return fmt.Errorf("%s must expand to at most %d characters", key, maxAPICharsetSize)
+	}
+	return nil
+}
+
+type strengthRequest struct {
+	Password    string            `json:"password"`
+	BannedWords []string          `json:"banned_words"`
+	Context     map[string]string `json:"context"`
+	// Policy, when given, is checked too; the context "user" value is used
+	// as the username.
+	Policy *pwdforge.Policy `json:"policy"`
+}
+
+type strengthResponse struct {
+	Strength    string        `json:"strength"`
+	Entropy     float64       `json:"entropy"`
+	Suggestions []string      `json:"suggestions"`
+	Policy      *policyReport `json:"policy,omitempty"`
+}
+
+type policyReport struct {
+	Passed bool                    `json:"passed"`
+	Rules  []pwdforge.PolicyResult `json:"rules"`
+}
+
+func handleStrength(w http.ResponseWriter, r *http.Request) {
+	var req strengthRequest
+	if err := server.DecodeJSON(r, &req); err != nil {
+		server.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := checkStrengthRequest(r.Context(), req)
+	if err != nil {
+		status := http.StatusBadGateway
+		if errors.As(err, new(requestError)) {
+			status = http.StatusBadRequest
+		}
+		server.WriteError(w, status, err.Error())
+		return
+	}
+	server.WriteJSON(w, http.StatusOK, resp)
+}
+
+func checkStrengthRequest(ctx context.Context, req strengthRequest) (strengthResponse, error) {
+	if req.Password == "" {
+		return strengthResponse{}, requestError{errors.New("password is required")}
+	}
+	banned := pwdforge.NewBlocklist(req.BannedWords...)
+	for _, value := range req.Context {
+		banned.AddContext(value)
+	}
+	s := pwdforge.CheckStrength(req.Password, banned)
+	resp := strengthResponse{Strength: s.Rating, Entropy: s.Entropy, Suggestions: s.Suggestions}
+	if req.Policy == nil {
+		return resp, nil
+	}
+	if len(req.Policy.BannedWordFiles) > 0 {
+		return strengthResponse{}, requestError{errors.New("banned_word_files cannot be used over the API; send banned_words instead")}
+	}
+	if err := req.Policy.Validate(); err != nil {
+		return strengthResponse{}, requestError{err}
+	}
+	req.Policy.Blocklist = banned
+	results, err := pwdforge.CheckPolicy(ctx, req.Policy, req.Password, pwdforge.PolicyOptions{Username: req.Context["user"]})
+	if err != nil {
+		return strengthResponse{}, err
+	}
+	resp.Policy = &policyReport{Passed: pwdforge.Passed(results), Rules: results}
+	return resp, nil
+}
+
+type pwnedRequest struct {
+	Password string `json:"password"`
+	// SHA1 lets clients send the hex SHA-1 of the password instead.
+	SHA1 string `json:"sha1"`
+}
+
+type pwnedResponse struct {
+	Pwned bool `json:"pwned"`
+	Count int  `json:"count"`
+}
+
+func handlePwned(w http.ResponseWriter, r *http.Request) {
+	var req pwnedRequest
+	if err := server.DecodeJSON(r, &req); err != nil {
+		server.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	resp, err := checkPwnedRequest(r.Context(), req)
+	if err != nil {
+		status := http.StatusBadGateway
+		if errors.As(err, new(requestError)) {
+			status = http.StatusBadRequest
+		}
+		server.WriteError(w, status, err.Error())
+		return
+	}
+	server.WriteJSON(w, http.StatusOK, resp)
+}
+
+func checkPwnedRequest(ctx context.Context, req pwnedRequest) (pwnedResponse, error) {
+	var resp pwnedResponse
+	var err error
+	switch {
+	case (req.Password == "") == (req.SHA1 == ""):
+		return resp, requestError{errors.New("send exactly one of password or sha1")}
+	case req.SHA1 != "":
+		if len(req.SHA1) != 40 {
+			return resp, requestError{errors.New("sha1 must be 40 hex characters")}
+		}
+		resp.Pwned, resp.Count, err = pwdforge.BreachChecker{}.CheckSHA1(ctx, req.SHA1)
+	default:
+		resp.Pwned, resp.Count, err = pwdforge.CheckPwned(ctx, req.Password)
+	}
+	if err != nil {
+		// A *pwdforge.BreachCheckError, which says the check failed.
+		return pwnedResponse{}, err
+	}
+	return resp, nil
+}
+
+func init() {
+	serveCmd.Flags().String("addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().String("token-file", "", "File with accepted bearer tokens, one per line (also $"+tokenEnv+")")
+	serveCmd.Flags().String("tls-cert", "", "TLS certificate (PEM) for HTTPS")
+	serveCmd.Flags().String("tls-key", "", "TLS private key (PEM) for HTTPS")
+	serveCmd.Flags().String("client-ca", "", "CA bundle (PEM) for client certificates; enables mTLS")
+	serveCmd.Flags().Float64("rate", 5, "Requests per second allowed per client (0 disables rate limiting)")
+	serveCmd.Flags().Int("burst", 20, "Requests a client may send at once before rate limiting")
+	serveCmd.Flags().Duration("shutdown-timeout", 10*time.Second, "How long in-flight requests may finish on shutdown")
+	serveCmd.Flags().String("log-format", "json", "Access log format: json, text")
+	RootCmd.AddCommand(serveCmd)
+}
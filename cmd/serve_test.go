@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleGenerateLimits(t *testing.T) {
+	tests := []struct {
+		name, body string
+		status     int
+		want       string
+	}{
+		{"ok", `{"length": 16, "count": 2}`, http.StatusOK, ""},
+		{"custom charset spec", `{"custom_charset": "` + strings.Repeat("ab", 200) + `"}`, http.StatusBadRequest, "custom_charset must be at most 256 characters"},
+		{"custom charset expansion", `{"custom_charset": "!-` + "\U0010FFFF" + `"}`, http.StatusBadRequest, "custom_charset must expand to at most 65536 characters"},
+		{"custom charset classes", `{"custom_charset": "` + strings.Repeat(`\\p{L}`, 40) + `"}`, http.StatusBadRequest, "custom_charset must expand to at most 65536 characters"},
+		{"exclude chars", `{"exclude_chars": "` + strings.Repeat(`\\p{Han}`, 20) + `"}`, http.StatusBadRequest, "exclude_chars must expand to at most 65536 characters"},
+		{"pattern class", `{"pattern": "[big]{8}", "pattern_classes": {"big": "\\p{Han}"}}`, http.StatusBadRequest, "pattern_classes.big must expand to at most 65536 characters"},
+		{"pattern class count", `{"pattern": "[a]", "pattern_classes": {` + classList(33) + `}}`, http.StatusBadRequest, "pattern_classes may define at most 32 classes"},
+		{"invalid charset", `{"custom_charset": "z-a"}`, http.StatusBadRequest, "custom_charset: charset \"z-a\""},
+		{"greek pattern", `{"pattern": "[g]{12}", "pattern_classes": {"g": "\\p{Greek}"}, "count": 3}`, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			rec := httptest.NewRecorder()
+			handleGenerate(rec, httptest.NewRequest(http.MethodPost, "/v1/generate", strings.NewReader(tt.body)))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if tt.want != "" {
+				var resp struct {
+					Error string `json:"error"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(resp.Error, tt.want) {
+					t.Errorf("error = %q, want it to contain %q", resp.Error, tt.want)
+				}
+			}
+			if d := time.Since(start); d > 2*time.Second {
+				t.Errorf("request took %v", d)
+			}
+		})
+	}
+}
+
+// classList returns n pattern class definitions as JSON object members.
+func classList(n int) string {
+	classes := make([]string, n)
+	for i := range classes {
+		classes[i] = `"c` + strings.Repeat("x", i) + `": "ab"`
+	}
+	return strings.Join(classes, ", ")
+}
@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"pwdforge/internal/server"
+	"pwdforge/pkg/pwdforge"
+	"pwdforge/pkg/pwdforgepb"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var serveGRPCCmd = &cobra.Command{
+	Use:   "serve-grpc",
+	Short: "Run the gRPC API for generation, strength and breach checks",
+	Long: `Serves the pwdforge.v1.PwdForge gRPC service (proto/pwdforge/v1/pwdforge.proto):
+Generate, CheckStrength, CheckPwned and the streaming CheckStrengthBatch and
+CheckPwnedBatch. Requests are validated, limited and authenticated exactly
+like "pwdforge serve": send "authorization: Bearer <token>" metadata or a
+client certificate. Go clients can use pwdforge/pkg/pwdforgeclient.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		addr, _ := cmd.Flags().GetString("addr")
+		tokenFile, _ := cmd.Flags().GetString("token-file")
+		tlsCert, _ := cmd.Flags().GetString("tls-cert")
+		tlsKey, _ := cmd.Flags().GetString("tls-key")
+		clientCA, _ := cmd.Flags().GetString("client-ca")
+		rate, _ := cmd.Flags().GetFloat64("rate")
+		burst, _ := cmd.Flags().GetInt("burst")
+		shutdownTimeout, _ := cmd.Flags().GetDuration("shutdown-timeout")
+		logFormat, _ := cmd.Flags().GetString("log-format")
+
+		var tokens []string
+		if tokenFile != "" {
+			var err error
+			if tokens, err = readTokens(tokenFile); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading token file: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if t := strings.TrimSpace(os.Getenv(tokenEnv)); t != "" {
+			tokens = append(tokens, t)
+		}
+
+		var handler slog.Handler = slog.NewJSONHandler(os.Stderr, nil)
+		if logFormat == "text" {
+			handler = slog.NewTextHandler(os.Stderr, nil)
+		}
+		srv, err := server.NewGRPC(server.Options{
+			Addr:            addr,
+			Tokens:          tokens,
+			TLSCert:         tlsCert,
+			TLSKey:          tlsKey,
+			ClientCA:        clientCA,
+			Rate:            rate,
+			Burst:           burst,
+			ShutdownTimeout: shutdownTimeout,
+			Logger:          slog.New(handler),
+		}, func(s *grpc.Server) {
+			pwdforgepb.RegisterPwdForgeServer(s, grpcService{})
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := srv.Run(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// grpcService implements pwdforgepb.PwdForgeServer on top of the same
+// request logic as the HTTP API.
+type grpcService struct {
+	pwdforgepb.UnimplementedPwdForgeServer
+}
+
+func (grpcService) Generate(ctx context.Context, req *pwdforgepb.GenerateRequest) (*pwdforgepb.GenerateResponse, error) {
+	secrets, err := generateSecrets(ctx, configFromProto(req))
+	if err != nil {
+		return nil, grpcError(err, codes.FailedPrecondition)
+	}
+	resp := &pwdforgepb.GenerateResponse{}
+	for _, s := range secrets {
+		resp.Passwords = append(resp.Passwords, &pwdforgepb.GeneratedSecret{
+			Password: s.Password,
+			Rendered: s.Rendered,
+			Hash:     s.Hash,
+			Strength: s.Strength,
+			Entropy:  s.Entropy,
+		})
+	}
+	return resp, nil
+}
+
+func (grpcService) CheckStrength(ctx context.Context, req *pwdforgepb.CheckStrengthRequest) (*pwdforgepb.CheckStrengthResponse, error) {
+	resp, err := checkStrengthProto(ctx, req)
+	if err != nil {
+		return nil, grpcError(err, codes.Unavailable)
+	}
+	return resp, nil
+}
+
+func (grpcService) CheckPwned(ctx context.Context, req *pwdforgepb.CheckPwnedRequest) (*pwdforgepb.CheckPwnedResponse, error) {
+	resp, err := checkPwnedProto(ctx, req)
+	if err != nil {
+		return nil, grpcError(err, codes.Unavailable)
+	}
+	return resp, nil
+}
+
+func (grpcService) CheckStrengthBatch(stream pwdforgepb.PwdForge_CheckStrengthBatchServer) error {
+	for index := uint64(0); ; index++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		result := &pwdforgepb.CheckStrengthResult{Index: index}
+		if result.Response, err = checkStrengthProto(stream.Context(), req); err != nil {
+			result.Error = err.Error()
+		}
+		if err := stream.Send(result); err != nil {
+			return err
+		}
+	}
+}
+
+func (grpcService) CheckPwnedBatch(stream pwdforgepb.PwdForge_CheckPwnedBatchServer) error {
+	for index := uint64(0); ; index++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		result := &pwdforgepb.CheckPwnedResult{Index: index}
+		if result.Response, err = checkPwnedProto(stream.Context(), req); err != nil {
+			result.Error = err.Error()
+		}
+		if err := stream.Send(result); err != nil {
+			return err
+		}
+	}
+}
+
+// grpcError maps request errors to InvalidArgument and everything else to
+// code.
+func grpcError(err error, code codes.Code) error {
+	if errors.As(err, new(requestError)) {
+		code = codes.InvalidArgument
+	}
+	return status.Error(code, err.Error())
+}
+
+func checkStrengthProto(ctx context.Context, req *pwdforgepb.CheckStrengthRequest) (*pwdforgepb.CheckStrengthResponse, error) {
+	r, err := checkStrengthRequest(ctx, strengthRequest{
+		Password:    req.GetPassword(),
+		BannedWords: req.GetBannedWords(),
+		Context:     req.GetContext(),
+		Policy:      policyFromProto(req.GetPolicy()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &pwdforgepb.CheckStrengthResponse{Strength: r.Strength, Entropy: r.Entropy, Suggestions: r.Suggestions}
+	if r.Policy != nil {
+		resp.Policy = &pwdforgepb.PolicyReport{Passed: r.Policy.Passed}
+		for _, rule := range r.Policy.Rules {
+			resp.Policy.Rules = append(resp.Policy.Rules, &pwdforgepb.PolicyRule{Rule: rule.Rule, Passed: rule.Passed, Detail: rule.Detail})
+		}
+	}
+	return resp, nil
+}
+
+func checkPwnedProto(ctx context.Context, req *pwdforgepb.CheckPwnedRequest) (*pwdforgepb.CheckPwnedResponse, error) {
+	r, err := checkPwnedRequest(ctx, pwnedRequest{Password: req.GetPassword(), SHA1: req.GetSha1()})
+	if err != nil {
+		return nil, err
+	}
+	return &pwdforgepb.CheckPwnedResponse{Pwned: r.Pwned, Count: int64(r.Count)}, nil
+}
+
+func configFromProto(req *pwdforgepb.GenerateRequest) generateRequest {
+	return generateRequest{Options: pwdforge.Options{
+		Length:          int(req.GetLength()),
+		Count:           int(req.GetCount()),
+		IncludeUpper:    req.GetIncludeUpper(),
+		IncludeLower:    req.GetIncludeLower(),
+		IncludeDigits:   req.GetIncludeDigits(),
+		IncludeSpecials: req.GetIncludeSpecials(),
+		ExcludeSimilar:  req.GetExcludeSimilar(),
+		ExcludeProfiles: req.GetExcludeProfiles(),
+		CustomCharset:   req.GetCustomCharset(),
+		ExcludeChars:    req.GetExcludeChars(),
+		EnforceAll:      req.GetEnforceAll(),
+		Passphrase:      req.GetPassphrase(),
+		WordCount:       int(req.GetWordCount()),
+		Pronounceable:   req.GetPronounceable(),
+		PIN:             req.GetPin(),
+		PINAllow:        req.GetPinAllow(),
+		Pattern:         req.GetPattern(),
+		PatternClasses:  req.GetPatternClasses(),
+		Type:            req.GetType(),
+		Bytes:           int(req.GetBytes()),
+		KeyPrefix:       req.GetKeyPrefix(),
+		Target:          req.GetTarget(),
+		Context:         req.GetContext(),
+		Policy:          policyFromProto(req.GetPolicy()),
+	}, Hash: req.GetHash(), HashOnly: req.GetHashOnly()}
+}
+
+func policyFromProto(p *pwdforgepb.Policy) *pwdforge.Policy {
+	if p == nil {
+		return nil
+	}
+	return &pwdforge.Policy{
+		MinLength:        int(p.GetMinLength()),
+		MaxLength:        int(p.GetMaxLength()),
+		RequireUpper:     p.GetRequireUpper(),
+		RequireLower:     p.GetRequireLower(),
+		RequireDigits:    p.GetRequireDigits(),
+		RequireSpecials:  p.GetRequireSpecials(),
+		RequireLetters:   p.GetRequireLetters(),
+		MinClasses:       int(p.GetMinClasses()),
+		MaxRepeated:      int(p.GetMaxRepeated()),
+		BannedWords:      p.GetBannedWords(),
+		DisallowUsername: p.GetDisallowUsername(),
+		CompanyNames:     p.GetCompanyNames(),
+		MinEntropy:       p.GetMinEntropy(),
+		NotPwned:         p.GetNotPwned(),
+	}
+}
+
+func init() {
+	serveGRPCCmd.Flags().String("addr", "127.0.0.1:9090", "Address to listen on")
+	serveGRPCCmd.Flags().String("token-file", "", "File with accepted bearer tokens, one per line (also $"+tokenEnv+")")
+	serveGRPCCmd.Flags().String("tls-cert", "", "TLS certificate (PEM)")
+	serveGRPCCmd.Flags().String("tls-key", "", "TLS private key (PEM)")
+	serveGRPCCmd.Flags().String("client-ca", "", "CA bundle (PEM) for client certificates; enables mTLS")
+	serveGRPCCmd.Flags().Float64("rate", 5, "Requests (or stream messages) per second allowed per client (0 disables rate limiting)")
+	serveGRPCCmd.Flags().Int("burst", 20, "Requests a client may send at once before rate limiting")
+	serveGRPCCmd.Flags().Duration("shutdown-timeout", 10*time.Second, "How long in-flight calls may finish on shutdown")
+	serveGRPCCmd.Flags().String("log-format", "json", "Access log format: json, text")
+	RootCmd.AddCommand(serveGRPCCmd)
+}
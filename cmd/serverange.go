@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"pwdforge/internal/pwnrange"
+	"pwdforge/internal/server"
+
+	"github.com/spf13/cobra"
+)
+
+var serveRangeCmd = &cobra.Command{
+	Use:   "serve-range",
+	Short: "Serve a local Pwned Passwords mirror from the downloaded dump",
+	Long: `Serves GET /range/{prefix} in the format of api.pwnedpasswords.com from a
+local copy of the Pwned Passwords dump, so checkpwn --api-url (and other HIBP
+clients) can check passwords without leaving the network.
+
+--dump takes a single sorted "HASH:COUNT" file, as written by the official
+downloader, or a directory of per-prefix <PREFIX>.txt files. SHA-1 and NTLM
+dumps are told apart by hash length; pass --dump twice to serve both, with
+NTLM answered for ?mode=ntlm. Single files are indexed on first start and
+the index is cached as <dump>.idx.
+
+Like the public API, responses are padded with zero-count entries when the
+request has "Add-Padding: true", and no authentication is required: range
+queries reveal only a 5-character hash prefix.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dumpPaths, _ := cmd.Flags().GetStringSlice("dump")
+		addr, _ := cmd.Flags().GetString("addr")
+		tlsCert, _ := cmd.Flags().GetString("tls-cert")
+		tlsKey, _ := cmd.Flags().GetString("tls-key")
+		rate, _ := cmd.Flags().GetFloat64("rate")
+		burst, _ := cmd.Flags().GetInt("burst")
+		shutdownTimeout, _ := cmd.Flags().GetDuration("shutdown-timeout")
+		logFormat, _ := cmd.Flags().GetString("log-format")
+		if len(dumpPaths) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: --dump must be provided.")
+			os.Exit(1)
+		}
+
+		var handler slog.Handler = slog.NewJSONHandler(os.Stderr, nil)
+		if logFormat == "text" {
+			handler = slog.NewTextHandler(os.Stderr, nil)
+		}
+		logger := slog.New(handler)
+
+		var sha1Dump, ntlmDump pwnrange.Dump
+		for _, path := range dumpPaths {
+			logger.Info("loading dump", "path", path)
+			start := time.Now()
+			d, err := pwnrange.Open(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading dump: %v\n", err)
+				os.Exit(1)
+			}
+			defer d.Close()
+			slot, mode := &sha1Dump, "sha1"
+			if d.HashLen() == pwnrange.NTLMLen {
+				slot, mode = &ntlmDump, "ntlm"
+			}
+			if *slot != nil {
+				fmt.Fprintf(os.Stderr, "Error: more than one %s dump given.\n", strings.ToUpper(mode))
+				os.Exit(1)
+			}
+			*slot = d
+			logger.Info("dump ready", "path", path, "mode", mode, "duration_ms", time.Since(start).Milliseconds())
+		}
+
+		srv, err := server.New(server.Options{
+			Addr:            addr,
+			Public:          true,
+			TLSCert:         tlsCert,
+			TLSKey:          tlsKey,
+			Rate:            rate,
+			Burst:           burst,
+			ShutdownTimeout: shutdownTimeout,
+			Logger:          logger,
+		}, rangeHandler(sha1Dump, ntlmDump))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := srv.Run(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// rangeHandler answers range queries like api.pwnedpasswords.com: plain
+// text "SUFFIX:COUNT" lines separated by CRLF. Either dump may be nil.
+func rangeHandler(sha1Dump, ntlmDump pwnrange.Dump) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /range/{prefix}", func(w http.ResponseWriter, r *http.Request) {
+		prefix, err := pwnrange.NormalizePrefix(r.PathValue("prefix"))
+		if err != nil {
+			http.Error(w, "The hash prefix was not in a valid format", http.StatusBadRequest)
+			return
+		}
+		var d pwnrange.Dump
+		switch mode := r.URL.Query().Get("mode"); strings.ToLower(mode) {
+		case "", "sha1":
+			d = sha1Dump
+		case "ntlm":
+			d = ntlmDump
+		default:
+			http.Error(w, "The mode was not in a valid format", http.StatusBadRequest)
+			return
+		}
+		if d == nil {
+			http.Error(w, "No dump loaded for this mode", http.StatusNotFound)
+			return
+		}
+		lines, err := d.Range(prefix)
+		if err != nil {
+			http.Error(w, "Error reading the dump", http.StatusInternalServerError)
+			return
+		}
+		if strings.EqualFold(r.Header.Get("Add-Padding"), "true") {
+			lines = pwnrange.Pad(lines, d.HashLen())
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Cache-Control", "public, max-age=2678400")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Vary", "Add-Padding")
+		fmt.Fprint(w, strings.Join(lines, "\r\n"))
+	})
+	return mux
+}
+
+func init() {
+	serveRangeCmd.Flags().StringSlice("dump", nil, "Pwned Passwords dump file or directory (SHA-1 or NTLM; repeat for both)")
+	serveRangeCmd.Flags().String("addr", "127.0.0.1:8080", "Address to listen on")
+	serveRangeCmd.Flags().String("tls-cert", "", "TLS certificate file (enables HTTPS)")
+	serveRangeCmd.Flags().String("tls-key", "", "TLS private key file")
+	serveRangeCmd.Flags().Float64("rate", 50, "Requests per second allowed per client IP (0 disables limiting)")
+	serveRangeCmd.Flags().Int("burst", 500, "Burst size of the per-client rate limit")
+	serveRangeCmd.Flags().Duration("shutdown-timeout", 10*time.Second, "How long to wait for in-flight requests on shutdown")
+	serveRangeCmd.Flags().String("log-format", "json", "Access log format: json, text")
+	RootCmd.AddCommand(serveRangeCmd)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"pwdforge/internal/pwnrange"
+	"pwdforge/pkg/pwdforge"
+)
+
+// TestRangeMirror serves a small dump with rangeHandler and checks that
+// padded responses still give the breach checker the right counts.
+func TestRangeMirror(t *testing.T) {
+	breached := map[string]int{"password": 9545824, "letmein": 412331, "hunter2": 23547}
+	var lines []string
+	for pw, count := range breached {
+		sum := sha1.Sum([]byte(pw))
+		lines = append(lines, fmt.Sprintf("%s:%d", strings.ToUpper(hex.EncodeToString(sum[:])), count))
+	}
+	slices.Sort(lines)
+	path := filepath.Join(t.TempDir(), "pwned-passwords-sha1.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dump, err := pwnrange.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dump.Close()
+	srv := httptest.NewServer(rangeHandler(dump, nil))
+	defer srv.Close()
+
+	checker := pwdforge.BreachChecker{APIURL: srv.URL}
+	for pw, want := range breached {
+		pwned, count, err := checker.Check(context.Background(), pw)
+		if err != nil || !pwned || count != want {
+			t.Errorf("Check(%q) = %v, %d, %v, want true, %d", pw, pwned, count, err, want)
+		}
+	}
+	// Neither the padding of a range with real entries nor that of an
+	// empty range reports a breach.
+	for _, pw := range []string{"correct horse battery staple", "Tr0ub4dor&3"} {
+		pwned, count, err := checker.Check(context.Background(), pw)
+		if err != nil || pwned || count != 0 {
+			t.Errorf("Check(%q) = %v, %d, %v, want false, 0", pw, pwned, count, err)
+		}
+	}
+
+	// The checker asks for padding, and the mirror pads.
+	sum := sha1.Sum([]byte("password"))
+	prefix := strings.ToUpper(hex.EncodeToString(sum[:]))[:pwnrange.PrefixLen]
+	for padding, least := range map[string]int{"true": 800, "": 1} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/range/"+strings.ToLower(prefix), nil)
+		if padding != "" {
+			req.Header.Set("Add-Padding", padding)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		entries := strings.Split(string(body), "\r\n")
+		if resp.StatusCode != http.StatusOK || len(entries) < least || (padding == "" && len(entries) != 1) {
+			t.Errorf("Add-Padding %q: status %d, %d entries", padding, resp.StatusCode, len(entries))
+		}
+	}
+
+	for _, url := range []string{"/range/ABCD", "/range/ABCDG", "/range/ABCDE?mode=md5"} {
+		resp, err := http.Get(srv.URL + url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("GET %s = %d, want 400", url, resp.StatusCode)
+		}
+	}
+	resp, err := http.Get(srv.URL + "/range/ABCDE?mode=ntlm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("NTLM query without an NTLM dump = %d, want 404", resp.StatusCode)
+	}
+}
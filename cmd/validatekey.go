@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"pwdforge/pkg/pwdforge"
+
+	"github.com/spf13/cobra"
+)
+
+var validateKeyCmd = &cobra.Command{
+	Use:   "validate-key [key...]",
+	Short: "Validate the checksum of API keys made by generate --type apikey",
+	Long:  "Checks the structure and CRC32 checksum of API keys offline. Exits with status 1 if any key is invalid.",
+	Run: func(cmd *cobra.Command, args []string) {
+		inputFile, _ := cmd.Flags().GetString("input")
+		prefix, _ := cmd.Flags().GetString("prefix")
+		format, _ := cmd.Flags().GetString("format")
+		keys := args
+		if inputFile != "" {
+			file, err := os.Open(inputFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error opening input file: %v\n", err)
+				os.Exit(1)
+			}
+			defer file.Close()
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				if key := strings.TrimSpace(scanner.Text()); key != "" {
+					keys = append(keys, key)
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading input file: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if len(keys) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: provide keys as arguments or with --input.")
+			os.Exit(1)
+		}
+
+		var results []map[string]interface{}
+		allValid := true
+		for _, key := range keys {
+			result := map[string]interface{}{"key": key, "valid": true, "error": ""}
+			got, err := pwdforge.ValidateAPIKey(key)
+			if err == nil && prefix != "" && got != prefix {
+				err = fmt.Errorf("prefix %q, expected %q", got, prefix)
+			}
+			if err != nil {
+				result["valid"] = false
+				result["error"] = err.Error()
+				allValid = false
+			}
+			results = append(results, result)
+		}
+
+		if format == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(results)
+		} else {
+			for _, r := range results {
+				if r["valid"].(bool) {
+					fmt.Printf("[+] '%s' is valid.\n", r["key"])
+				} else {
+					fmt.Printf("[!] '%s' is invalid: %s\n", r["key"], r["error"])
+				}
+			}
+		}
+		if !allValid {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	validateKeyCmd.Flags().String("input", "", "Read keys to validate from a file (one per line)")
+	validateKeyCmd.Flags().String("prefix", "", "Require this key prefix")
+	validateKeyCmd.Flags().String("format", "plain", "Output format: plain, json")
+	RootCmd.AddCommand(validateKeyCmd)
+}
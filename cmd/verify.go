@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"pwdforge/pkg/pwdforge"
+
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check candidate passwords against an existing hash",
+	Long: `Tests one password (hidden prompt, or stdin when piped) or a file of
+candidates (one per line) against a hash and reports which ones match.
+Supports bcrypt, argon2id/argon2i, scrypt, crypt(3) SHA-512/SHA-256/MD5/APR1,
+PBKDF2 (passlib and Django) and PostgreSQL SCRAM-SHA-256 verifiers.
+Exits with status 1 if no candidate matches.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		encoded, _ := cmd.Flags().GetString("hash")
+		inputFile, _ := cmd.Flags().GetString("input")
+		format, _ := cmd.Flags().GetString("format")
+		encoded = strings.TrimSpace(encoded)
+		if encoded == "" {
+			fmt.Fprintln(os.Stderr, "Error: --hash must be provided.")
+			os.Exit(1)
+		}
+		alg, err := pwdforge.IdentifyHash(encoded)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "[+] Hash format: %s\n", alg)
+
+		var candidates []string
+		if inputFile != "" {
+			file, err := os.Open(inputFile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error opening input file: %v\n", err)
+				os.Exit(1)
+			}
+			defer file.Close()
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				// Leading and trailing spaces can be part of a password.
+				if pw := strings.TrimRight(scanner.Text(), "\r"); pw != "" {
+					candidates = append(candidates, pw)
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading input file: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			pw, err := readSecret("Password: ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading password: %v\n", err)
+				os.Exit(1)
+			}
+			candidates = append(candidates, string(pw))
+		}
+
+		var results []map[string]interface{}
+		matched := 0
+		for _, pw := range candidates {
+			ok, err := pwdforge.VerifyHash(encoded, pw)
+			if err != nil {
+				// The hash is malformed; every candidate would fail the same way.
+				fmt.Fprintf(os.Stderr, "Error verifying password: %v\n", err)
+				os.Exit(1)
+			}
+			if ok {
+				matched++
+			}
+			results = append(results, map[string]interface{}{"password": pw, "match": ok})
+		}
+
+		if format == "json" {
+			// A prompted password is never echoed back.
+			if inputFile == "" {
+				delete(results[0], "password")
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(results)
+		} else if inputFile == "" {
+			if matched > 0 {
+				fmt.Println("[+] Password matches.")
+			} else {
+				fmt.Println("[!] Password does not match.")
+			}
+		} else {
+			for _, r := range results {
+				if r["match"].(bool) {
+					fmt.Printf("[+] '%s' matches.\n", r["password"])
+				}
+			}
+			if matched == 0 {
+				fmt.Printf("[!] None of %d candidates match.\n", len(candidates))
+			}
+		}
+		if matched == 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	verifyCmd.Flags().String("hash", "", "Hash to test candidates against (quote it: hashes contain '$')")
+	verifyCmd.Flags().String("input", "", "Read candidate passwords from a file (one per line)")
+	verifyCmd.Flags().String("format", "plain", "Output format: plain, json")
+	RootCmd.AddCommand(verifyCmd)
+}
@@ -0,0 +1,436 @@
+// Package batch reads batch files of generation parameters: JSON or YAML
+// objects one per line, a JSON array, YAML documents, or CSV with a header
+// row. Every entry keeps its position in the file so that problems can be
+// reported by line and column.
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Input formats. Auto picks one from the file extension and contents.
+const (
+	Auto  = "auto"
+	Lines = "lines"
+	JSON  = "json"
+	YAML  = "yaml"
+	CSV   = "csv"
+)
+
+// Formats lists the accepted format names.
+var Formats = []string{Auto, Lines, JSON, YAML, CSV}
+
+// Error is a problem at a position in a batch file. Column is 0 when only
+// the line is known.
+type Error struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Entry is one object of a batch file.
+type Entry struct {
+	// Line and Column locate the start of the entry.
+	Line   int
+	Column int
+	node   *yaml.Node
+}
+
+// Read calls fn for every entry of the batch file at path in order. A
+// syntax error stops reading with an *Error, except in the Lines format,
+// where it is passed to skip with the line's number so that the caller may
+// report it and go on; a nil skip makes it fatal. An error from fn stops
+// reading and is returned as is.
+func Read(path, format string, fn func(Entry) error, skip func(*Error) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if format == Auto || format == "" {
+		if format, err = detect(path, f); err != nil {
+			return err
+		}
+	}
+	switch format {
+	case Lines:
+		return readLines(f, fn, skip)
+	case JSON:
+		return readJSON(f, fn)
+	case YAML:
+		return readYAML(f, fn)
+	case CSV:
+		return readCSV(f, fn)
+	}
+	return fmt.Errorf("unknown batch format %q (want %s)", format, strings.Join(Formats, ", "))
+}
+
+// detect picks the format from the extension and contents: .yaml and .yml
+// are YAML documents, .csv is CSV, a file starting with "[" is a JSON
+// array, and anything else holds one object per line.
+func detect(path string, f *os.File) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return YAML, nil
+	case ".csv":
+		return CSV, nil
+	}
+	format := Lines
+	r := bufio.NewReader(f)
+	for {
+		b, err := r.ReadByte()
+		if err != nil || !isSpace(b) {
+			if b == '[' {
+				format = JSON
+			}
+			break
+		}
+	}
+	_, err := f.Seek(0, io.SeekStart)
+	return format, err
+}
+
+func isSpace(b byte) bool { return b == ' ' || b == '\t' || b == '\r' || b == '\n' }
+
+// readLines reads one JSON or YAML flow object per line. Blank lines and
+// lines starting with "#" are skipped.
+func readLines(r io.Reader, fn func(Entry) error, skip func(*Error) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		node, err := parse([]byte(line), lineNo)
+		if err == nil && node.Kind != yaml.MappingNode {
+			err = &Error{Line: lineNo, Column: node.Column, Err: errors.New("expected an object")}
+		}
+		if err != nil {
+			var perr *Error
+			if skip == nil || !errors.As(err, &perr) {
+				return err
+			}
+			if err := skip(perr); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(Entry{Line: node.Line, Column: node.Column, node: node}); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// readJSON reads a JSON array of objects, or a single object.
+func readJSON(r io.Reader, fn func(Entry) error) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if !json.Valid(data) {
+		return jsonSyntaxError(data, 1)
+	}
+	node, err := parse(data, 1)
+	if err != nil {
+		return err
+	}
+	return entries(node, fn)
+}
+
+// readYAML reads YAML documents separated by "---". Each document is an
+// object or a list of objects.
+func readYAML(r io.Reader, fn func(Entry) error) error {
+	dec := yaml.NewDecoder(r)
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return yamlSyntaxError(err, 1)
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		if err := entries(doc.Content[0], fn); err != nil {
+			return err
+		}
+	}
+}
+
+// entries calls fn for node, an object, or for every object in node, a
+// list.
+func entries(node *yaml.Node, fn func(Entry) error) error {
+	items := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		items = node.Content
+	}
+	for _, item := range items {
+		if item.Kind != yaml.MappingNode {
+			return &Error{Line: item.Line, Column: item.Column, Err: errors.New("expected an object")}
+		}
+		if err := fn(Entry{Line: item.Line, Column: item.Column, node: item}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readCSV reads CSV with a header row of keys. Empty cells leave a key
+// unset; cells starting with "[" or "{" are YAML flow lists and objects,
+// e.g. "[similar, shell]". Lines starting with "#" are skipped.
+func readCSV(r io.Reader, fn func(Entry) error) error {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = 0
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return csvError(err)
+	}
+	keys := make([]*yaml.Node, len(header))
+	for i, h := range header {
+		line, col := cr.FieldPos(i)
+		keys[i] = &yaml.Node{Kind: yaml.ScalarNode, Value: strings.TrimSpace(h), Line: line, Column: col}
+	}
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return csvError(err)
+		}
+		line, col := cr.FieldPos(0)
+		node := &yaml.Node{Kind: yaml.MappingNode, Line: line, Column: col}
+		for i, cell := range record {
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				continue
+			}
+			cellLine, cellCol := cr.FieldPos(i)
+			value := &yaml.Node{Kind: yaml.ScalarNode, Value: cell, Line: cellLine, Column: cellCol}
+			if cell[0] == '[' || cell[0] == '{' {
+				if value, err = parse([]byte(cell), cellLine); err != nil {
+					var perr *Error
+					if errors.As(err, &perr) && perr.Column > 0 {
+						perr.Column += cellCol - 1
+					}
+					return err
+				}
+				shift(value, 0, cellCol-1)
+			}
+			key := *keys[i]
+			key.Line, key.Column = cellLine, cellCol
+			node.Content = append(node.Content, &key, value)
+		}
+		if err := fn(Entry{Line: line, Column: col, node: node}); err != nil {
+			return err
+		}
+	}
+}
+
+func csvError(err error) error {
+	var perr *csv.ParseError
+	if errors.As(err, &perr) {
+		return &Error{Line: perr.Line, Column: perr.Column, Err: perr.Err}
+	}
+	return err
+}
+
+// parse parses data, which starts at line line of the file, into a node
+// whose positions are relative to the file. JSON syntax errors are
+// reported with their column.
+func parse(data []byte, line int) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && !json.Valid(data) {
+			return nil, jsonSyntaxError(data, line)
+		}
+		return nil, yamlSyntaxError(err, line)
+	}
+	if len(doc.Content) == 0 {
+		return nil, &Error{Line: line, Err: errors.New("empty entry")}
+	}
+	node := doc.Content[0]
+	shift(node, line-1, 0)
+	return node, nil
+}
+
+// shift moves node and its children down by lines and right by columns.
+func shift(node *yaml.Node, lines, columns int) {
+	node.Line += lines
+	node.Column += columns
+	for _, c := range node.Content {
+		shift(c, lines, columns)
+	}
+}
+
+// jsonSyntaxError locates the JSON syntax error in data, which starts at
+// line line of the file.
+func jsonSyntaxError(data []byte, line int) error {
+	var v any
+	err := json.Unmarshal(data, &v)
+	var serr *json.SyntaxError
+	if !errors.As(err, &serr) {
+		return &Error{Line: line, Err: fmt.Errorf("invalid JSON: %v", err)}
+	}
+	offset := int(serr.Offset)
+	before := data[:min(offset, len(data))]
+	l := line + bytes.Count(before, []byte("\n"))
+	col := offset - bytes.LastIndexByte(before, '\n')
+	return &Error{Line: l, Column: max(col-1, 1), Err: fmt.Errorf("invalid JSON: %v", err)}
+}
+
+var yamlLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// yamlSyntaxError turns a YAML parse error for data starting at line line
+// of the file into an *Error.
+func yamlSyntaxError(err error, line int) error {
+	if m := yamlLine.FindStringSubmatch(err.Error()); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return &Error{Line: line + n - 1, Err: fmt.Errorf("invalid YAML: %s", m[2])}
+	}
+	return &Error{Line: line, Err: fmt.Errorf("invalid YAML: %s", strings.TrimPrefix(err.Error(), "yaml: "))}
+}
+
+// Pos returns the position of the entry's top-level key, or the entry's
+// own position when the key is not set.
+func (e Entry) Pos(key string) (line, column int) {
+	for i := 0; i+1 < len(e.node.Content); i += 2 {
+		if k := e.node.Content[i]; k.Value == key {
+			return k.Line, k.Column
+		}
+	}
+	return e.Line, e.Column
+}
+
+// Decode decodes e into v, a pointer to a struct with yaml tags. Keys that
+// v has no field for are returned in unknown rather than failing, so the
+// caller can decide whether they are fatal; values of the wrong type fail
+// with an *Error.
+func (e Entry) Decode(v any) (unknown []*Error, err error) {
+	unknown = unknownKeys(e.node, reflect.TypeOf(v), "")
+	if err := e.node.Decode(v); err != nil {
+		var terr *yaml.TypeError
+		if errors.As(err, &terr) && len(terr.Errors) > 0 {
+			return unknown, typeError(e.node, terr.Errors[0])
+		}
+		return unknown, &Error{Line: e.Line, Column: e.Column, Err: err}
+	}
+	return unknown, nil
+}
+
+var typeLine = regexp.MustCompile("^line (\\d+): (cannot unmarshal (!!\\w+)(?: `([^`]*)`)?.*)$")
+
+// typeError turns a yaml type error message for an entry node into an
+// *Error, locating the offending value by its line, tag and, for scalars,
+// text.
+func typeError(node *yaml.Node, msg string) error {
+	m := typeLine.FindStringSubmatch(msg)
+	if m == nil {
+		return &Error{Line: node.Line, Column: node.Column, Err: errors.New(msg)}
+	}
+	line, _ := strconv.Atoi(m[1])
+	err := &Error{Line: line, Err: errors.New(m[2])}
+	tag := m[3]
+	// Long values are shortened to "abc..." in the message.
+	value := strings.TrimSuffix(m[4], "...")
+	var find func(*yaml.Node) bool
+	find = func(n *yaml.Node) bool {
+		if n.Line == line && n.ShortTag() == tag && strings.HasPrefix(n.Value, value) {
+			err.Column = n.Column
+			return true
+		}
+		return slices.ContainsFunc(n.Content, find)
+	}
+	find(node)
+	return err
+}
+
+// unknownKeys reports the keys of the mapping node that t, a struct or a
+// pointer to one, has no yaml field for, and recurses into struct-valued
+// fields. Maps accept any key.
+func unknownKeys(node *yaml.Node, t reflect.Type, prefix string) []*Error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	fields := yamlFields(t)
+	var errs []*Error
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		ft, ok := fields[key.Value]
+		if !ok {
+			errs = append(errs, &Error{Line: key.Line, Column: key.Column, Err: fmt.Errorf("unknown key %q", prefix+key.Value)})
+			continue
+		}
+		errs = append(errs, unknownKeys(value, ft, prefix+key.Value+".")...)
+	}
+	return errs
+}
+
+// yamlFields maps the yaml keys of struct t, including inlined structs, to
+// their field types.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(opts, "inline") {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			for k, v := range yamlFields(ft) {
+				fields[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
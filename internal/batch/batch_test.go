@@ -0,0 +1,193 @@
+package batch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// params is a cut-down version of the generate batch parameters.
+type params struct {
+	Length  int    `yaml:"length"`
+	Name    string `yaml:"name"`
+	Options struct {
+		Upper bool `yaml:"upper"`
+	} `yaml:"options"`
+}
+
+// read writes content to a file called name and reads it in format,
+// returning the entries and the errors passed to skip.
+func read(t *testing.T, name, format, content string) ([]Entry, []*Error, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var entries []Entry
+	var skipped []*Error
+	err := Read(path, format, func(e Entry) error {
+		entries = append(entries, e)
+		return nil
+	}, func(e *Error) error {
+		skipped = append(skipped, e)
+		return nil
+	})
+	return entries, skipped, err
+}
+
+func TestReadSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name, file, content string
+		line, column        int
+		msg                 string
+	}{
+		{"json trailing comma", "b.json", "[\n  {\"length\": 12},\n  {\"length\": 12,}\n]\n",
+			3, 17, "invalid JSON: invalid character '}' looking for beginning of object key string"},
+		{"json unterminated", "b.json", "[\n  {\"length\": 12}\n",
+			3, 1, "invalid JSON: unexpected end of JSON input"},
+		{"json non-object", "b.json", "[{\"length\": 1}, 5]",
+			1, 17, "expected an object"},
+		{"yaml indentation", "b.yaml", "- length: 12\n  name: a\n- length: 16\n   name: b\n",
+			4, 0, "invalid YAML: mapping values are not allowed in this context"},
+		{"yaml second document", "b.yml", "length: 12\n---\nname: a\n length: 16\n",
+			4, 0, "invalid YAML: mapping values are not allowed in this context"},
+		{"yaml scalar", "b.yaml", "length: 12\n---\n- 16\n",
+			3, 3, "expected an object"},
+		{"csv bare quote", "b.csv", "length,name\n12,ok\n16,a\"b\n",
+			3, 5, `bare " in non-quoted-field`},
+		{"csv field count", "b.csv", "length,name\n12,ok\n16,a,extra\n",
+			3, 1, "wrong number of fields"},
+		{"csv flow cell", "b.csv", "length,name\n12,ok\n16,{a: [1}\n",
+			3, 5, "invalid JSON: invalid character 'a' looking for beginning of object key string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := read(t, tt.file, Auto, tt.content)
+			var berr *Error
+			if !errors.As(err, &berr) {
+				t.Fatalf("Read = %v, want an *Error", err)
+			}
+			if berr.Line != tt.line || berr.Column != tt.column || berr.Err.Error() != tt.msg {
+				t.Errorf("Read error = line %d, column %d: %v\nwant line %d, column %d: %s",
+					berr.Line, berr.Column, berr.Err, tt.line, tt.column, tt.msg)
+			}
+		})
+	}
+}
+
+func TestReadLinesSkip(t *testing.T) {
+	content := strings.Join([]string{
+		`# one object per line`,
+		`{"length": 12}`,
+		`{"length": 12`,
+		``,
+		`42`,
+		`{length: 16, name: [a}`,
+		`{length: 20, name: b}`,
+	}, "\n")
+	entries, skipped, err := read(t, "batch.txt", Auto, content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Line != 2 || entries[1].Line != 7 {
+		t.Errorf("entries = %+v, want lines 2 and 7", entries)
+	}
+	want := []string{
+		"line 3, column 13: invalid JSON: unexpected end of JSON input",
+		"line 5, column 1: expected an object",
+		"line 6, column 2: invalid JSON: invalid character 'l' looking for beginning of object key string",
+	}
+	if len(skipped) != len(want) {
+		t.Fatalf("skipped %v, want %d errors", skipped, len(want))
+	}
+	for i, e := range skipped {
+		if e.Error() != want[i] {
+			t.Errorf("skipped[%d] = %q, want %q", i, e, want[i])
+		}
+	}
+
+	// Without skip the first bad line is fatal.
+	path := filepath.Join(t.TempDir(), "batch.txt")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err = Read(path, Lines, func(Entry) error { return nil }, nil)
+	var berr *Error
+	if !errors.As(err, &berr) || berr.Line != 3 {
+		t.Errorf("Read without skip = %v, want an error on line 3", err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name, file, content string
+		unknown             []string
+		err                 string
+	}{
+		{"json type", "b.json", "[\n  {\"length\": 12},\n  {\"name\": \"x\", \"length\": \"twelve\"}\n]",
+			nil, "line 3, column 27: cannot unmarshal !!str `twelve` into int"},
+		{"yaml type", "b.yaml", "length: 12\n---\nname: x\nlength:\n  - 16\n",
+			nil, "line 5, column 3: cannot unmarshal !!seq into int"},
+		{"csv type", "b.csv", "name,length\nx,12\ny,sixteen\n",
+			nil, "line 3, column 3: cannot unmarshal !!str `sixteen` into int"},
+		{"unknown keys", "b.yaml", "lenght: 12\noptions:\n  upper: true\n  digts: true\n",
+			[]string{"line 1, column 1: unknown key \"lenght\"", "line 4, column 3: unknown key \"options.digts\""}, ""},
+		{"unknown csv column", "b.csv", "length,colour\n12,red\n",
+			[]string{"line 2, column 4: unknown key \"colour\""}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, _, err := read(t, tt.file, Auto, tt.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var unknown []string
+			var decodeErr error
+			for _, e := range entries {
+				var p params
+				u, err := e.Decode(&p)
+				for _, ue := range u {
+					unknown = append(unknown, ue.Error())
+				}
+				if err != nil {
+					decodeErr = err
+				}
+			}
+			if strings.Join(unknown, "\n") != strings.Join(tt.unknown, "\n") {
+				t.Errorf("unknown keys = %q, want %q", unknown, tt.unknown)
+			}
+			got := ""
+			if decodeErr != nil {
+				got = decodeErr.Error()
+			}
+			if got != tt.err {
+				t.Errorf("Decode error = %q, want %q", got, tt.err)
+			}
+		})
+	}
+}
+
+func TestEntryPos(t *testing.T) {
+	entries, _, err := read(t, "b.csv", Auto, "# comment\nname,length\nx,12\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	e := entries[0]
+	if line, col := e.Pos("length"); line != 3 || col != 3 {
+		t.Errorf("Pos(length) = %d:%d, want 3:3", line, col)
+	}
+	if line, col := e.Pos("missing"); line != 3 || col != 1 {
+		t.Errorf("Pos(missing) = %d:%d, want the entry's 3:1", line, col)
+	}
+}
+
+func TestReadUnknownFormat(t *testing.T) {
+	if _, _, err := read(t, "b.txt", "toml", "x"); err == nil || !strings.HasPrefix(err.Error(), `unknown batch format "toml"`) {
+		t.Errorf("Read = %v", err)
+	}
+}
@@ -0,0 +1,318 @@
+// Package config layers settings from several sources, such as built-in
+// defaults, config files, environment variables and command-line flags,
+// and remembers which source each value came from.
+//
+// Settings are structs whose fields are pointers, slices or maps with yaml
+// tags. A nil field is unset and leaves the value of lower layers in place,
+// so that a layer can set a boolean to false or a list to [] explicitly.
+// Anonymous struct fields tagged ",inline" are merged field by field.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Default is the source name of built-in defaults.
+const Default = "default"
+
+// Sources maps the yaml key of each set value to the name of the layer it
+// came from.
+type Sources map[string]string
+
+// Merge copies every set field of src over dst, which must point to the
+// same struct type, and records name as the source of those keys.
+// Pointers are copied, not written through, so dst and src may share
+// values safely.
+func Merge(dst, src any, name string, sources Sources) {
+	d, s := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src)
+	if s.Kind() == reflect.Pointer {
+		if s.IsNil() {
+			return
+		}
+		s = s.Elem()
+	}
+	if d.Type() != s.Type() {
+		panic(fmt.Sprintf("config: merging %s into %s", s.Type(), d.Type()))
+	}
+	merge(d, s, name, sources)
+}
+
+func merge(d, s reflect.Value, name string, sources Sources) {
+	t := d.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		key, inline, ok := yamlKey(f)
+		switch {
+		case !ok:
+		case inline:
+			merge(d.Field(i), s.Field(i), name, sources)
+		case !s.Field(i).IsNil():
+			d.Field(i).Set(s.Field(i))
+			if sources != nil {
+				sources[key] = name
+			}
+		}
+	}
+}
+
+// yamlKey returns the yaml key of f, or inline for embedded structs. ok is
+// false for fields that are not settings.
+func yamlKey(f reflect.StructField) (key string, inline, ok bool) {
+	if !f.IsExported() {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	switch {
+	case name == "-":
+		return "", false, false
+	case strings.Contains(opts, "inline"):
+		return "", true, true
+	case name == "":
+		name = strings.ToLower(f.Name)
+	}
+	return name, false, true
+}
+
+// Node renders the set fields of v, a pointer to settings, as a YAML
+// mapping. With sources, each key is followed by a comment naming the
+// layer its value came from.
+func Node(v any, sources Sources) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	err := appendFields(node, reflect.ValueOf(v).Elem(), sources)
+	return node, err
+}
+
+func appendFields(node *yaml.Node, v reflect.Value, sources Sources) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		key, inline, ok := yamlKey(t.Field(i))
+		switch {
+		case !ok:
+			continue
+		case inline:
+			if err := appendFields(node, v.Field(i), sources); err != nil {
+				return err
+			}
+			continue
+		case v.Field(i).IsNil():
+			continue
+		}
+		var value yaml.Node
+		if err := value.Encode(v.Field(i).Interface()); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		k := &yaml.Node{Kind: yaml.ScalarNode, Value: key}
+		if source, ok := sources[key]; ok {
+			k.LineComment = source
+		}
+		node.Content = append(node.Content, k, &value)
+	}
+	return nil
+}
+
+// Layer is one source of settings.
+type Layer struct {
+	// Name says where the values come from, e.g. Default or a file path.
+	Name string
+	// Values points to settings; nil adds nothing.
+	Values any
+}
+
+// Resolve merges layers into dst in order, each overriding the ones
+// before, and returns the source of every set value.
+func Resolve(dst any, layers []Layer) Sources {
+	sources := Sources{}
+	for _, l := range layers {
+		if l.Values != nil {
+			Merge(dst, l.Values, l.Name, sources)
+		}
+	}
+	return sources
+}
+
+// SystemPath is the config file shared by every user of the machine.
+const SystemPath = "/etc/pwdforge/config.yaml"
+
+// UserPath returns the user's config file, pwdforge/config.yaml in
+// $XDG_CONFIG_HOME (~/.config when unset) on Unix and in the platform's
+// config directory elsewhere, or "" when there is no home directory.
+func UserPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pwdforge", "config.yaml")
+}
+
+// EnvPrefix starts the names of environment variables that set options.
+const EnvPrefix = "PWDFORGE_"
+
+// EnvName returns the environment variable for a setting: EnvPrefix and
+// the parts, upper-cased and joined by "_", with "-" replaced by "_". For
+// example EnvName("policy", "check", "format") is PWDFORGE_POLICY_CHECK_FORMAT.
+func EnvName(parts ...string) string {
+	name := strings.Join(parts, "_")
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// FromEnv sets the fields of v, a pointer to settings, from the
+// environment variables named by EnvName with the yaml key, such as
+// PWDFORGE_LENGTH. Fields of nested structs use the key path, such as
+// PWDFORGE_POLICY_MIN_LENGTH. Lists are comma-separated and maps are
+// comma-separated key=value pairs. It returns the variable each key was
+// read from.
+func FromEnv(v any, lookup func(string) (string, bool)) (map[string]string, error) {
+	vars := map[string]string{}
+	_, err := fromEnv(reflect.ValueOf(v).Elem(), nil, lookup, vars)
+	return vars, err
+}
+
+// fromEnv fills the struct v from variables below path and reports
+// whether any was set.
+func fromEnv(v reflect.Value, path []string, lookup func(string) (string, bool), vars map[string]string) (bool, error) {
+	t := v.Type()
+	found := false
+	for i := range t.NumField() {
+		key, inline, ok := yamlKey(t.Field(i))
+		if !ok {
+			continue
+		}
+		if inline {
+			set, err := fromEnv(v.Field(i), path, lookup, vars)
+			if err != nil {
+				return false, err
+			}
+			found = found || set
+			continue
+		}
+		field := v.Field(i)
+		keyPath := append(append([]string(nil), path...), key)
+		if ft := field.Type(); ft.Kind() == reflect.Pointer && ft.Elem().Kind() == reflect.Struct {
+			nested := reflect.New(ft.Elem())
+			set, err := fromEnv(nested.Elem(), keyPath, lookup, vars)
+			if err != nil {
+				return false, err
+			}
+			if set {
+				field.Set(nested)
+				found = true
+			}
+			continue
+		}
+		name := EnvName(keyPath...)
+		s, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setString(field, s); err != nil {
+			return false, fmt.Errorf("%s: %w", name, err)
+		}
+		vars[strings.Join(keyPath, ".")] = name
+		found = true
+	}
+	return found, nil
+}
+
+// setString parses s into v, allocating pointers.
+func setString(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		p := reflect.New(v.Type().Elem())
+		if err := setString(p.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(p)
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		list := reflect.MakeSlice(v.Type(), 0, 0)
+		for _, item := range splitList(s) {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setString(elem, item); err != nil {
+				return err
+			}
+			list = reflect.Append(list, elem)
+		}
+		v.Set(list)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for _, pair := range splitList(s) {
+			k, val, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("invalid key=value pair %q", pair)
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(k)), reflect.ValueOf(strings.TrimSpace(val)))
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("cannot be set from the environment")
+	}
+	return nil
+}
+
+// splitList splits a comma-separated list, trimming spaces; "" is empty.
+func splitList(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	items := strings.Split(s, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
+
+// FlagValue renders a YAML value as a command-line flag value: scalars as
+// written, lists comma-separated and maps as comma-separated key=value
+// pairs.
+func FlagValue(node *yaml.Node) (string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value, nil
+	case yaml.SequenceNode:
+		items := make([]string, len(node.Content))
+		for i, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return "", fmt.Errorf("line %d: expected a list of values", item.Line)
+			}
+			items[i] = item.Value
+		}
+		return strings.Join(items, ","), nil
+	case yaml.MappingNode:
+		pairs := make([]string, 0, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			if v.Kind != yaml.ScalarNode {
+				return "", fmt.Errorf("line %d: expected key: value pairs", v.Line)
+			}
+			pairs = append(pairs, k.Value+"="+v.Value)
+		}
+		return strings.Join(pairs, ","), nil
+	}
+	return "", fmt.Errorf("line %d: unsupported value", node.Line)
+}
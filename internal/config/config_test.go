@@ -0,0 +1,154 @@
+package config
+
+import (
+	"maps"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Common is inlined into settings, as GenerateOptions is into the generate
+// settings; inlined structs must be exported.
+type Common struct {
+	Length *int     `yaml:"length"`
+	Upper  *bool    `yaml:"upper"`
+	Words  []string `yaml:"words"`
+}
+
+type settings struct {
+	Common  `yaml:",inline"`
+	Name    *string           `yaml:"name"`
+	Labels  map[string]string `yaml:"labels"`
+	Nested  *nested           `yaml:"nested"`
+	Skipped *string           `yaml:"-"`
+}
+
+type nested struct {
+	MinLength *int `yaml:"min_length"`
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// TestResolvePrecedence layers defaults, a file, a profile, the environment
+// and flags, each overriding the ones before only for the keys it sets.
+func TestResolvePrecedence(t *testing.T) {
+	defaults := &settings{Common: Common{Length: ptr(20), Upper: ptr(true)}, Name: ptr("default")}
+	file := &settings{Common: Common{Length: ptr(16), Words: []string{"a", "b"}}, Name: ptr("file")}
+	profile := &settings{Common: Common{Upper: ptr(false)}, Name: ptr("profile")}
+	var env settings
+	vars, err := FromEnv(&env, lookupMap(map[string]string{
+		"PWDFORGE_LENGTH": "24",
+		"PWDFORGE_NAME":   "env",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"length": "PWDFORGE_LENGTH", "name": "PWDFORGE_NAME"}; !maps.Equal(vars, want) {
+		t.Errorf("FromEnv vars = %v, want %v", vars, want)
+	}
+	flags := &settings{Name: ptr("flag")}
+
+	var got settings
+	sources := Resolve(&got, []Layer{
+		{Default, defaults},
+		{"config.yaml", file},
+		{"config.yaml (profile ci)", profile},
+		{"environment", &env},
+		{"empty", nil},
+		{"command line", flags},
+	})
+	want := settings{
+		Common: Common{Length: ptr(24), Upper: ptr(false), Words: []string{"a", "b"}},
+		Name:   ptr("flag"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Resolve = %+v, want %+v", got, want)
+	}
+	wantSources := Sources{
+		"length": "environment",
+		"upper":  "config.yaml (profile ci)", // an explicit false overrides true
+		"words":  "config.yaml",
+		"name":   "command line",
+	}
+	if !maps.Equal(sources, wantSources) {
+		t.Errorf("sources = %v, want %v", sources, wantSources)
+	}
+	// Resolving copies pointers; it never writes through them.
+	if *defaults.Length != 20 || *file.Name != "file" {
+		t.Error("Resolve modified a layer")
+	}
+}
+
+func TestMergeEmptyList(t *testing.T) {
+	// An empty, non-nil list is set and clears the lower layer's list.
+	dst := settings{Common: Common{Words: []string{"a"}}}
+	Merge(&dst, &settings{Common: Common{Words: []string{}}}, "file", nil)
+	if dst.Words == nil || len(dst.Words) != 0 {
+		t.Errorf("Words = %#v, want an empty list", dst.Words)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	var s settings
+	vars, err := FromEnv(&s, lookupMap(map[string]string{
+		"PWDFORGE_UPPER":             "false",
+		"PWDFORGE_WORDS":             " a, b ,c",
+		"PWDFORGE_LABELS":            "team=sec, env = prod",
+		"PWDFORGE_NESTED_MIN_LENGTH": "12",
+		"PWDFORGE_SKIPPED":           "ignored",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := settings{
+		Common: Common{Upper: ptr(false), Words: []string{"a", "b", "c"}},
+		Labels: map[string]string{"team": "sec", "env": "prod"},
+		Nested: &nested{MinLength: ptr(12)},
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("FromEnv = %+v, want %+v", s, want)
+	}
+	if vars["nested.min_length"] != "PWDFORGE_NESTED_MIN_LENGTH" {
+		t.Errorf("vars = %v", vars)
+	}
+
+	for env, msg := range map[string]string{
+		"PWDFORGE_LENGTH": `PWDFORGE_LENGTH: invalid integer "x"`,
+		"PWDFORGE_UPPER":  `PWDFORGE_UPPER: invalid boolean "x"`,
+		"PWDFORGE_LABELS": `PWDFORGE_LABELS: invalid key=value pair "x"`,
+	} {
+		var s settings
+		if _, err := FromEnv(&s, lookupMap(map[string]string{env: "x"})); err == nil || err.Error() != msg {
+			t.Errorf("FromEnv with %s=x: %v, want %s", env, err, msg)
+		}
+	}
+}
+
+func TestNodeSources(t *testing.T) {
+	s := settings{Common: Common{Length: ptr(16)}, Name: ptr("x")}
+	node, err := Node(&s, Sources{"length": "config.yaml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "length: 16 # config.yaml\nname: x\n"; string(out) != want {
+		t.Errorf("Node = %q, want %q", out, want)
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	if got := EnvName("policy", "check", "min-length"); got != "PWDFORGE_POLICY_CHECK_MIN_LENGTH" {
+		t.Errorf("EnvName = %s", got)
+	}
+}
+
+func lookupMap(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
@@ -0,0 +1,170 @@
+// Package credfile edits colon-separated credential files such as htpasswd
+// and /etc/shadow, where each line starts with "user:".
+package credfile
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoUser is returned when the requested user has no entry.
+var ErrNoUser = errors.New("user not found")
+
+// ValidUser rejects names that would corrupt the file format.
+func ValidUser(user string) error {
+	if user == "" || strings.ContainsAny(user, ":\n\r") {
+		return fmt.Errorf("invalid user name %q", user)
+	}
+	return nil
+}
+
+// Read returns the lines of path. A missing file yields no lines.
+func Read(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// Find returns the index of user's line, or -1.
+func Find(lines []string, user string) int {
+	for i, line := range lines {
+		if strings.HasPrefix(line, user+":") {
+			return i
+		}
+	}
+	return -1
+}
+
+// Lookup returns the colon-separated fields of user's line.
+func Lookup(path, user string) ([]string, error) {
+	lines, err := Read(path)
+	if err != nil {
+		return nil, err
+	}
+	i := Find(lines, user)
+	if i < 0 {
+		return nil, ErrNoUser
+	}
+	return strings.Split(lines[i], ":"), nil
+}
+
+// Update rewrites path atomically with the lines returned by fn. The new
+// contents go to a temporary file in the same directory, which is synced,
+// given the original file's mode and owner (or newMode for a new file) and
+// renamed over path, so readers never see a partial file. When path is a
+// symlink the file it points to is replaced. Concurrent Updates of the same
+// file hold an advisory lock and run one after another, so none loses the
+// others' changes.
+func Update(path string, newMode os.FileMode, fn func(lines []string) ([]string, error)) error {
+	path, err := resolve(path)
+	if err != nil {
+		return err
+	}
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	lines, err := Read(path)
+	if err != nil {
+		return err
+	}
+	lines, err = fn(lines)
+	if err != nil {
+		return err
+	}
+
+	mode := newMode
+	info, statErr := os.Stat(path)
+	if statErr == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	for _, line := range lines {
+		w.WriteString(line)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if statErr == nil {
+		if err := copyOwner(tmp, info); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// resolve follows the symlinks in path. A file that does not exist yet is
+// resolved through its directory; a symlink to a missing file is an error
+// rather than being replaced.
+func resolve(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err == nil {
+		return resolved, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return "", fmt.Errorf("%s is a symlink to a missing file", path)
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Dir(path))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filepath.Base(path)), nil
+}
+
+// lock takes an exclusive advisory lock on the hidden file .<name>.lock
+// next to path and returns the function that releases it. The lock file is
+// left in place: removing it would let a waiting process lock a file that
+// the next one no longer sees.
+func lock(path string) (func(), error) {
+	name := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".lock")
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("locking %s: %w", path, err)
+	}
+	// Closing the file releases the lock.
+	return func() { f.Close() }, nil
+}
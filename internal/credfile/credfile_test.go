@@ -0,0 +1,102 @@
+package credfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func addUser(user string) func([]string) ([]string, error) {
+	return func(lines []string) ([]string, error) {
+		return append(lines, user+":hash"), nil
+	}
+}
+
+func TestUpdateConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "htpasswd")
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- Update(path, 0o640, addUser(fmt.Sprintf("user%d", i)))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	lines, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range writers {
+		if Find(lines, fmt.Sprintf("user%d", i)) < 0 {
+			t.Errorf("user%d was lost; file has %d lines", i, len(lines))
+		}
+	}
+}
+
+func TestUpdateSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "real", "htpasswd")
+	if err := os.Mkdir(filepath.Dir(target), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("alice:old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "htpasswd")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	if err := Update(link, 0o640, addUser("bob")); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("the symlink was replaced: %v, %v", info, err)
+	}
+	lines, err := Read(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"alice:old", "bob:hash"}; !slices.Equal(lines, want) {
+		t.Errorf("target has %q, want %q", lines, want)
+	}
+	if info, err := os.Stat(target); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("target mode = %v, %v, want 0600", info.Mode().Perm(), err)
+	}
+}
+
+func TestUpdateDanglingSymlink(t *testing.T) {
+	dir := t.TempDir()
+	link := filepath.Join(dir, "htpasswd")
+	if err := os.Symlink(filepath.Join(dir, "missing"), link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	if err := Update(link, 0o640, addUser("bob")); err == nil {
+		t.Error("Update through a dangling symlink succeeded")
+	}
+}
+
+func TestUpdateNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shadow")
+	if err := Update(path, 0o640, addUser("root")); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0o640 {
+		t.Errorf("new file mode = %v, %v, want 0640", info.Mode().Perm(), err)
+	}
+	if fields, err := Lookup(path, "root"); err != nil || fields[1] != "hash" {
+		t.Errorf("Lookup = %q, %v", fields, err)
+	}
+}
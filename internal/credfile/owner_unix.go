@@ -0,0 +1,30 @@
+//go:build !windows
+
+package credfile
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// copyOwner gives f the owner and group of the file described by info, so
+// replacing /etc/shadow keeps root:shadow. Failing to change ownership is
+// ignored when the owner and group already match, as they do for a user
+// editing a file they own in a directory of their own group.
+func copyOwner(f *os.File, info os.FileInfo) error {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	err := f.Chown(int(st.Uid), int(st.Gid))
+	if err == nil {
+		return nil
+	}
+	if cur, serr := f.Stat(); serr == nil {
+		if cst, ok := cur.Sys().(*syscall.Stat_t); ok && cst.Uid == st.Uid && cst.Gid == st.Gid {
+			return nil
+		}
+	}
+	return fmt.Errorf("keeping the owner of %s: %w", info.Name(), err)
+}
@@ -0,0 +1,166 @@
+package generator
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MinBlocklistWordLength is the shortest entry kept from word list files and
+// context values; shorter ones would match far too many passwords.
+const MinBlocklistWordLength = 3
+
+// leetFolds maps look-alike characters onto one canonical letter. Both the
+// banned words and the password are folded, so "P@ssw0rd", "pa55word" and
+// "PASSWORD" all match "password". 'l' and '1' fold to 'i' like '!' and '|'
+// because each of them stands in for the others.
+var leetFolds = map[rune]rune{
+	'0': 'o',
+	'1': 'i', '!': 'i', '|': 'i', 'l': 'i',
+	'3': 'e',
+	'4': 'a', '@': 'a',
+	'5': 's', '$': 's',
+	'7': 't', '+': 't',
+	'8': 'b',
+	'9': 'g',
+}
+
+// NormalizeLeet lower-cases s and folds l33t substitutions. It maps rune for
+// rune, so substrings of the result line up with substrings of s.
+func NormalizeLeet(s string) string {
+	return strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if f, ok := leetFolds[r]; ok {
+			return f
+		}
+		return r
+	}, s)
+}
+
+// Blocklist matches banned words anywhere in a password, ignoring case and
+// l33t substitutions. Words are kept in a hash set and matching probes only
+// the word lengths present, so lists with millions of entries stay fast.
+type Blocklist struct {
+	words   map[string]string // normalised form -> word as given
+	lengths []int             // distinct rune lengths of the normalised words
+}
+
+// NewBlocklist returns a blocklist of words. Empty strings are ignored.
+func NewBlocklist(words ...string) *Blocklist {
+	b := &Blocklist{words: map[string]string{}}
+	for _, w := range words {
+		b.Add(w)
+	}
+	return b
+}
+
+// Add bans word.
+func (b *Blocklist) Add(word string) {
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return
+	}
+	norm := NormalizeLeet(word)
+	if _, ok := b.words[norm]; ok {
+		return
+	}
+	b.words[norm] = word
+	if n := utf8.RuneCountInString(norm); !slices.Contains(b.lengths, n) {
+		b.lengths = append(b.lengths, n)
+		slices.Sort(b.lengths)
+	}
+}
+
+// AddContext bans a context value such as a user or service name, together
+// with its parts: "alice.smith@corp" also bans "alice", "smith" and "corp".
+func (b *Blocklist) AddContext(value string) {
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range append(parts, value) {
+		if utf8.RuneCountInString(w) >= MinBlocklistWordLength {
+			b.Add(w)
+		}
+	}
+}
+
+// Load adds every line of r, skipping blank lines, '#' comments and entries
+// shorter than MinBlocklistWordLength.
+func (b *Blocklist) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") || utf8.RuneCountInString(line) < MinBlocklistWordLength {
+			continue
+		}
+		b.Add(line)
+	}
+	return scanner.Err()
+}
+
+// LoadFile adds the words of a file, one per line.
+func (b *Blocklist) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return b.Load(f)
+}
+
+// Merge adds every word of other to b.
+func (b *Blocklist) Merge(other *Blocklist) {
+	if other == nil {
+		return
+	}
+	for _, w := range other.words {
+		b.Add(w)
+	}
+}
+
+// MergeBlocklists returns a blocklist with the words of both, reusing one
+// of them when the other is nil.
+func MergeBlocklists(a, b *Blocklist) *Blocklist {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	merged := NewBlocklist()
+	merged.Merge(a)
+	merged.Merge(b)
+	return merged
+}
+
+// Len returns the number of distinct banned words.
+func (b *Blocklist) Len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.words)
+}
+
+// Match returns the first banned word found in password. A nil Blocklist
+// matches nothing.
+func (b *Blocklist) Match(password string) (string, bool) {
+	if b == nil || len(b.words) == 0 {
+		return "", false
+	}
+	norm := []rune(NormalizeLeet(password))
+	for i := range norm {
+		for _, n := range b.lengths {
+			if i+n > len(norm) {
+				break
+			}
+			if w, ok := b.words[string(norm[i:i+n])]; ok {
+				return w, true
+			}
+		}
+	}
+	return "", false
+}
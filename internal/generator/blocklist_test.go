@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeLeet(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"P@ssw0rd", "password"},
+		{"pa55word", "password"},
+		{"PASSWORD", "password"},
+		{"$3cr3t", "secret"},
+		{"l1|!", "iiii"},
+		{"7r0ub4d0r+3", "troubadorte"},
+		{"8ig9uy", "bigguy"},
+		{"ÄBÇ", "äbç"},
+		{"2fa#6", "2fa#6"}, // unmapped digits and symbols are kept
+	}
+	for _, tt := range tests {
+		if got := NormalizeLeet(tt.in); got != tt.want {
+			t.Errorf("NormalizeLeet(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBlocklistMatch(t *testing.T) {
+	b := NewBlocklist("password", "Dragon", "letmein", "admin")
+	tests := []struct {
+		password, want string // want is empty for no match
+	}{
+		{"P@ssw0rd", "password"},
+		{"xxPA55WORDxx", "password"},
+		{"pa$$w0rd2024", "password"},
+		{"dr@g0n", "Dragon"},
+		{"DRAGON", "Dragon"},
+		{"1etme1n", "letmein"}, // '1' and 'l' both fold to 'i'
+		{"|3tm3!n", "letmein"},
+		{"letmeln", "letmein"},
+		{"4dm!n", "admin"},
+		{"@dm1n#", "admin"},
+		// Near misses: a missing, swapped or extra letter, or a fold that
+		// does not exist.
+		{"passwrd", ""},
+		{"pasword", ""},
+		{"psasword", ""},
+		{"pass-word", ""},
+		{"dr4gom", ""},
+		{"2dmin", ""}, // '2' is not an 'a'
+		{"letme_n", ""},
+		{"adm1", ""},
+	}
+	for _, tt := range tests {
+		got, ok := b.Match(tt.password)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("Match(%q) = %q, %v, want %q", tt.password, got, ok, tt.want)
+		}
+	}
+}
+
+func TestBlocklistLeetWords(t *testing.T) {
+	// Banned words are folded too, so a list entry written in l33t matches
+	// the plain spelling and every other variant.
+	b := NewBlocklist("p@55w0rd")
+	for _, pw := range []string{"password", "PASSWORD", "pa$sword", "p4ssw0rd"} {
+		if w, ok := b.Match(pw); !ok || w != "p@55w0rd" {
+			t.Errorf("Match(%q) = %q, %v", pw, w, ok)
+		}
+	}
+	if b.Len() != 1 {
+		t.Errorf("Len = %d", b.Len())
+	}
+	b.Add("PASSWORD")
+	if b.Len() != 1 {
+		t.Errorf("Len after adding a variant = %d, want 1", b.Len())
+	}
+}
+
+func TestBlocklistContextAndLoad(t *testing.T) {
+	b := NewBlocklist()
+	b.AddContext("alice.smith@corp")
+	for _, pw := range []string{"Al1ce!", "5m1th", "c0rp", "xALICE.SMITH@CORPx"} {
+		if _, ok := b.Match(pw); !ok {
+			t.Errorf("context blocklist does not match %q", pw)
+		}
+	}
+	if err := b.Load(strings.NewReader("# comment\n\nab\n  qwerty  \n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.Match("qw3rty"); !ok {
+		t.Error("loaded word does not match")
+	}
+	if w, ok := b.Match("abab"); ok {
+		t.Errorf("short entry was loaded: matched %q", w)
+	}
+	if _, ok := (*Blocklist)(nil).Match("password"); ok {
+		t.Error("nil blocklist matched")
+	}
+}
@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// ParseCharset expands a charset spec into its distinct runes, in first-seen
+// order. A spec may contain literal characters, ranges such as a-z or α-ω,
+// Unicode scripts, categories or properties such as \p{Greek} or \p{Lu}, and
+// backslash escapes (\- \\ \p). A '-' at either end of the spec is literal.
+func ParseCharset(spec string) ([]rune, error) {
+	seen := map[rune]bool{}
+	var out []rune
+	add := func(r rune) {
+		if !seen[r] {
+			seen[r] = true
+			out = append(out, r)
+		}
+	}
+	err := walkCharset(spec, func(lo, hi rune) {
+		for r := lo; r <= hi; r++ {
+			add(r)
+		}
+	}, func(table *unicode.RangeTable) {
+		for _, r := range tableRunes(table) {
+			add(r)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CharsetSize returns the number of runes ParseCharset would expand spec
+// to, counting overlapping ranges and classes once per occurrence, without
+// expanding it. Servers use it to bound the work a spec can cause.
+func CharsetSize(spec string) (int, error) {
+	size := 0
+	err := walkCharset(spec, func(lo, hi rune) {
+		size += int(hi-lo) + 1
+	}, func(table *unicode.RangeTable) {
+		for _, rng := range table.R16 {
+			size += int(rng.Hi-rng.Lo)/int(rng.Stride) + 1
+		}
+		for _, rng := range table.R32 {
+			size += int(rng.Hi-rng.Lo)/int(rng.Stride) + 1
+		}
+	})
+	return size, err
+}
+
+// walkCharset parses spec, calling span for every literal character (as a
+// one-rune range) and range, and class for every Unicode class.
+func walkCharset(spec string, span func(lo, hi rune), class func(*unicode.RangeTable)) error {
+	src := []rune(spec)
+	for i := 0; i < len(src); i++ {
+		lo := src[i]
+		if lo == '\\' {
+			if i+1 == len(src) {
+				return fmt.Errorf("charset %q: dangling escape at position %d", spec, i+1)
+			}
+			i++
+			if src[i] == 'p' {
+				end, table, err := parseUnicodeClass(src, i)
+				if err != nil {
+					return fmt.Errorf("charset %q: %v", spec, err)
+				}
+				class(table)
+				i = end
+				continue
+			}
+			lo = src[i]
+		}
+		if i+2 < len(src) && src[i+1] == '-' {
+			hi := src[i+2]
+			next := i + 2
+			if hi == '\\' {
+				if i+3 == len(src) {
+					return fmt.Errorf("charset %q: dangling escape at position %d", spec, i+3)
+				}
+				hi = src[i+3]
+				next = i + 3
+			}
+			if hi < lo {
+				return fmt.Errorf("charset %q: range %c-%c at position %d is reversed", spec, lo, hi, i+1)
+			}
+			span(lo, hi)
+			i = next
+			continue
+		}
+		span(lo, lo)
+	}
+	return nil
+}
+
+// parseUnicodeClass parses the {Name} following \p at src[i] and returns the
+// index of the closing brace and the matching range table.
+func parseUnicodeClass(src []rune, i int) (int, *unicode.RangeTable, error) {
+	if i+1 >= len(src) || src[i+1] != '{' {
+		return 0, nil, fmt.Errorf(`expected \p{Name} at position %d`, i)
+	}
+	end := indexRune(src, '}', i+1)
+	if end < 0 {
+		return 0, nil, fmt.Errorf(`unterminated \p{ at position %d`, i)
+	}
+	name := string(src[i+2 : end])
+	for _, tables := range []map[string]*unicode.RangeTable{unicode.Scripts, unicode.Categories, unicode.Properties} {
+		if t, ok := tables[name]; ok {
+			return end, t, nil
+		}
+	}
+	return 0, nil, fmt.Errorf("unknown Unicode class %q at position %d", name, i+3)
+}
+
+// tableRunes lists the printable runes in t.
+func tableRunes(t *unicode.RangeTable) []rune {
+	var out []rune
+	for _, rng := range t.R16 {
+		for r := rune(rng.Lo); r <= rune(rng.Hi); r += rune(rng.Stride) {
+			if unicode.IsGraphic(r) {
+				out = append(out, r)
+			}
+		}
+	}
+	for _, rng := range t.R32 {
+		for r := rune(rng.Lo); r <= rune(rng.Hi); r += rune(rng.Stride) {
+			if unicode.IsGraphic(r) {
+				out = append(out, r)
+			}
+		}
+	}
+	return out
+}
+
+// SubtractRunes returns set without any rune in exclude.
+func SubtractRunes(set, exclude []rune) []rune {
+	drop := map[rune]bool{}
+	for _, r := range exclude {
+		drop[r] = true
+	}
+	var out []rune
+	for _, r := range set {
+		if !drop[r] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// GenerateFromCharset returns a password of length runes drawn uniformly
+// from charset using random, or crypto/rand when nil.
+func GenerateFromCharset(random io.Reader, charset []rune, length int) (string, error) {
+	if len(charset) == 0 {
+		return "", fmt.Errorf("custom charset: %w", ErrEmptyCharset)
+	}
+	idx := make([]int, length)
+	if err := randomIndexes(random, len(charset), idx); err != nil {
+		return "", err
+	}
+	pw := make([]rune, length)
+	for i, n := range idx {
+		pw[i] = charset[n]
+	}
+	return string(pw), nil
+}
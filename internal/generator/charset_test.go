@@ -0,0 +1,133 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestParseCharset(t *testing.T) {
+	tests := []struct {
+		spec, want string
+	}{
+		{"", ""},
+		{"abc", "abc"},
+		{"a-f", "abcdef"},
+		{"0-9a-c", "0123456789abc"},
+		{"cab-d", "cabd"}, // first-seen order, each rune once
+		{"a-a", "a"},
+		{"-a-c", "-abc"},
+		{"a-c-", "abc-"},
+		{"-", "-"},
+		{"--/", "-./"},
+		{`a\-c`, "a-c"},
+		{`\\`, `\`},
+		{`\\-^`, `\]^`},
+		{`!-\-`, "!\"#$%&'()*+,-"},
+		{`\a-\c`, "abc"},
+		{"α-ε", "αβγδε"},
+		{"ünï", "ünï"},
+	}
+	for _, tt := range tests {
+		got, err := ParseCharset(tt.spec)
+		if err != nil {
+			t.Errorf("ParseCharset(%q): %v", tt.spec, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("ParseCharset(%q) = %q, want %q", tt.spec, string(got), tt.want)
+		}
+	}
+}
+
+func TestParseCharsetClasses(t *testing.T) {
+	tests := []struct {
+		spec  string
+		table *unicode.RangeTable
+		has   string
+	}{
+		{`\p{Greek}`, unicode.Greek, "αΩ"},
+		{`\p{Lu}`, unicode.Lu, "AZÉ"},
+		{`\p{Nd}`, unicode.Nd, "09٣"},
+		{`\p{Hex_Digit}`, unicode.Hex_Digit, "0aF"},
+	}
+	for _, tt := range tests {
+		got, err := ParseCharset(tt.spec)
+		if err != nil {
+			t.Errorf("ParseCharset(%q): %v", tt.spec, err)
+			continue
+		}
+		for _, r := range got {
+			if !unicode.Is(tt.table, r) || !unicode.IsGraphic(r) {
+				t.Errorf("ParseCharset(%q) contains %q", tt.spec, r)
+				break
+			}
+		}
+		for _, r := range tt.has {
+			if !strings.ContainsRune(string(got), r) {
+				t.Errorf("ParseCharset(%q) lacks %q", tt.spec, r)
+			}
+		}
+	}
+
+	// Classes mix with literals and ranges, and overlaps count once.
+	got, err := ParseCharset(`0-9\p{Nd}x`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(got), "0123456789") || got[len(got)-1] != 'x' || strings.Count(string(got), "0") != 1 {
+		t.Errorf("ParseCharset mixed spec = %q", string(got))
+	}
+}
+
+func TestParseCharsetErrors(t *testing.T) {
+	tests := []struct {
+		spec, want string
+	}{
+		{`\`, `charset "\\": dangling escape at position 1`},
+		{`abc\`, `charset "abc\\": dangling escape at position 4`},
+		{`a-\`, `charset "a-\\": dangling escape at position 3`},
+		{"z-a", `charset "z-a": range z-a at position 1 is reversed`},
+		{"ab9-0", `charset "ab9-0": range 9-0 at position 3 is reversed`},
+		{`\z-\a`, `charset "\\z-\\a": range z-a at position 2 is reversed`},
+		{"ω-α", `charset "ω-α": range ω-α at position 1 is reversed`},
+		{`\p`, `charset "\\p": expected \p{Name} at position 1`},
+		{`ab\pL`, `charset "ab\\pL": expected \p{Name} at position 3`},
+		{`\p{Greek`, `charset "\\p{Greek": unterminated \p{ at position 1`},
+		{`\p{}`, `charset "\\p{}": unknown Unicode class "" at position 4`},
+		{`a\p{Klingon}`, `charset "a\\p{Klingon}": unknown Unicode class "Klingon" at position 5`},
+		{`\p{greek}`, `charset "\\p{greek}": unknown Unicode class "greek" at position 4`},
+	}
+	for _, tt := range tests {
+		_, err := ParseCharset(tt.spec)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("ParseCharset(%q) error = %v, want %s", tt.spec, err, tt.want)
+		}
+		if _, err := CharsetSize(tt.spec); err == nil {
+			t.Errorf("CharsetSize(%q) accepted the spec", tt.spec)
+		}
+	}
+}
+
+func TestCharsetSize(t *testing.T) {
+	for _, spec := range []string{"", "abc", "a-z0-9", `\p{Greek}`, `\p{Lu}\p{Nd}!-/`, "α-ω"} {
+		set, err := ParseCharset(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size, err := CharsetSize(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size < len(set) {
+			t.Errorf("CharsetSize(%q) = %d, below the %d runes it expands to", spec, size, len(set))
+		}
+		if !strings.Contains(spec, `\p`) && size != len(set) {
+			t.Errorf("CharsetSize(%q) = %d, want %d", spec, size, len(set))
+		}
+	}
+	// Overlaps count once per occurrence.
+	if size, _ := CharsetSize("a-za-z"); size != 52 {
+		t.Errorf("CharsetSize(a-za-z) = %d, want 52", size)
+	}
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExclusionProfiles are named sets of characters that cause trouble in a
+// particular context. They can be combined; the union is removed from the
+// charset before generation.
+var ExclusionProfiles = map[string]string{
+	// Characters that are easily confused with each other when read.
+	"similar": "iIlL1oO0",
+	// Brackets, quotes and punctuation that are ambiguous in many fonts or
+	// need quoting in YAML and similar formats.
+	"ambiguous": "{}[]()/\\'\"`~,;:.<>",
+	// Characters with special meaning to POSIX shells and cmd.exe.
+	"shell": "$`\"'\\!&|;<>()*?[]{}#~%^= ",
+	// Reserved and unsafe characters in URLs (RFC 3986) and connection strings.
+	"url": ":/?#[]@!$&'()*+,;=%\"<>\\^`{|} ",
+	// Characters that must be escaped in XML text and attributes.
+	"xml": "<>&'\"",
+	// Quotes, comment starters, wildcards and escapes in SQL literals.
+	"sql": "'\";\\-%_/*",
+}
+
+// ExclusionProfileNames returns the profile names in sorted order.
+func ExclusionProfileNames() []string {
+	var names []string
+	for name := range ExclusionProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExclusionChars returns the union of the characters in the named profiles.
+func ExclusionChars(profiles []string) (string, error) {
+	var b strings.Builder
+	for _, p := range profiles {
+		name := strings.ToLower(strings.TrimSpace(p))
+		if name == "" {
+			continue
+		}
+		chars, ok := ExclusionProfiles[name]
+		if !ok {
+			return "", fmt.Errorf("unknown exclusion profile %q (want one of %s)", p, strings.Join(ExclusionProfileNames(), ", "))
+		}
+		b.WriteString(chars)
+	}
+	return b.String(), nil
+}
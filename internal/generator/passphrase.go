@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strings"
+)
+
+// DefaultWordlist is the built-in passphrase wordlist (short, for demo).
+var DefaultWordlist = []string{
+	"apple", "banana", "cat", "dog", "elephant", "fish", "grape", "hat", "ice", "jungle",
+	"kite", "lemon", "monkey", "nest", "orange", "pear", "queen", "rose", "sun", "tree",
+	"umbrella", "violet", "wolf", "xray", "yak", "zebra",
+}
+
+// GeneratePassphrase joins wordCount random words from wordlist (the
+// default list when empty) with "-" and returns it with its entropy. Words
+// are drawn from random, or crypto/rand when nil.
+func GeneratePassphrase(random io.Reader, wordCount int, wordlist []string) (string, float64, error) {
+	if wordCount <= 0 {
+		return "", 0, errors.New("a passphrase needs at least one word")
+	}
+	if len(wordlist) == 0 {
+		wordlist = DefaultWordlist
+	}
+	words := make([]string, wordCount)
+	for i := range words {
+		idx, err := randomIndex(random, len(wordlist))
+		if err != nil {
+			return "", 0, err
+		}
+		words[i] = wordlist[idx]
+	}
+	return strings.Join(words, "-"), float64(wordCount) * math.Log2(float64(len(wordlist))), nil
+}
@@ -1,106 +1,94 @@
 package generator
 
 import (
-    "crypto/rand"
-    "io"
-    mrand "math/rand"   // For rand.Seed
-    "time"        // For time.Now()
-    "os"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
 )
 
 type PasswordConfig struct {
-    Length          int
-    Count           int
-    IncludeUpper    bool
-    IncludeLower    bool
-    IncludeDigits   bool
-    IncludeSpecials bool
-    ExcludeSimilar  bool
+	Length          int
+	Count           int
+	IncludeUpper    bool
+	IncludeLower    bool
+	IncludeDigits   bool
+	IncludeSpecials bool
+	ExcludeSimilar  bool
+	ExcludeChars    string
+	// Rand is the source of randomness; nil means crypto/rand.
+	Rand io.Reader
 }
 
 const (
-    lowerChars = "abcdefghijklmnopqrstuvwxyz"
-    upperChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
-    digitChars = "0123456789"
-    specialChars = "!@#$%^&*()-_=+[]{}|;:,.<>/?"
+	lowerChars   = "abcdefghijklmnopqrstuvwxyz"
+	upperChars   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars   = "0123456789"
+	specialChars = "!@#$%^&*()-_=+[]{}|;:,.<>/?"
 )
 
-var reader io.Reader = rand.Reader
-
-func GeneratePasswords(config PasswordConfig) []string {
-    var charset string
-
-    if config.IncludeLower {
-        charset += lowerChars
-    }
-    if config.IncludeUpper {
-        charset += upperChars
-    }
-    if config.IncludeDigits {
-        charset += digitChars
-    }
-    if config.IncludeSpecials {
-        charset += specialChars
-    }
-
-    if config.ExcludeSimilar {
-        charset = removeSimilar(charset)
-    }
-
-    if len(charset) == 0 {
-        panic("no character set selected for password generation")
-    }
+// ErrEmptyCharset is returned when the selected classes and exclusions
+// leave no characters to generate from.
+var ErrEmptyCharset = errors.New("no characters left to generate from")
 
-    mrand.Seed(time.Now().UnixNano())
+var reader io.Reader = rand.Reader
 
-    var passwords []string
-    for i := 0; i < config.Count; i++ {
-        password := make([]byte, config.Length)
-        for j := 0; j < config.Length; j++ {
-            num, _ := randomUint8()
-            password[j] = charset[num%uint8(len(charset))]
-        }
-        passwords = append(passwords, string(password))
-    }
-    return passwords
+// PasswordCharset returns the characters GeneratePasswords draws from.
+func PasswordCharset(config PasswordConfig) string {
+	var charset string
+	if config.IncludeLower {
+		charset += lowerChars
+	}
+	if config.IncludeUpper {
+		charset += upperChars
+	}
+	if config.IncludeDigits {
+		charset += digitChars
+	}
+	if config.IncludeSpecials {
+		charset += specialChars
+	}
+	if config.ExcludeSimilar {
+		charset = removeSimilar(charset)
+	}
+	if config.ExcludeChars != "" {
+		charset = removeChars(charset, config.ExcludeChars)
+	}
+	return charset
 }
 
-func randomUint8() (uint8, error) {
-    b := make([]byte, 1)
-    _, err := reader.Read(b)
-    return b[0], err
-}
+func GeneratePasswords(config PasswordConfig) ([]string, error) {
+	charset := PasswordCharset(config)
+	if len(charset) == 0 {
+		return nil, fmt.Errorf("%w: select at least one character class", ErrEmptyCharset)
+	}
 
-func removeSimilar(s string) string {
-    similars := "iIlL1oO0"
-    for _, ch := range similars {
-        s = removeChar(s, byte(ch))
-    }
-    return s
+	var passwords []string
+	for i := 0; i < config.Count; i++ {
+		idx := make([]int, config.Length)
+		if err := randomIndexes(config.Rand, len(charset), idx); err != nil {
+			return nil, err
+		}
+		password := make([]byte, config.Length)
+		for j, n := range idx {
+			password[j] = charset[n]
+		}
+		passwords = append(passwords, string(password))
+	}
+	return passwords, nil
 }
 
-func removeChar(s string, c byte) string {
-    var result []byte
-    for i := 0; i < len(s); i++ {
-        if s[i] != c {
-            result = append(result, s[i])
-        }
-    }
-    return string(result)
+func removeSimilar(s string) string {
+	return removeChars(s, ExclusionProfiles["similar"])
 }
 
-func SavePasswordsToFile(passwords []string, filename string) error {
-    file, err := os.Create(filename)
-    if err != nil {
-        return err
-    }
-    defer file.Close()
-
-    for _, pwd := range passwords {
-        _, err := file.WriteString(pwd + "\n")
-        if err != nil {
-            return err
-        }
-    }
-    return nil
+// removeChars drops every character of chars from s.
+func removeChars(s, chars string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(chars, r) {
+			return -1
+		}
+		return r
+	}, s)
 }
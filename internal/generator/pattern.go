@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Pattern placeholders. Any other character is copied literally; a
+// backslash makes the next character literal, {n} repeats the previous
+// element n times and [name] draws from a custom class.
+var patternClasses = map[rune]string{
+	'L': lowerChars + upperChars,
+	'U': upperChars,
+	'l': lowerChars,
+	'D': digitChars,
+	'd': digitChars,
+	'S': specialChars,
+	'A': lowerChars + upperChars + digitChars,
+	'X': lowerChars + upperChars + digitChars + specialChars,
+	'C': "BCDFGHJKLMNPQRSTVWXYZ",
+	'c': "bcdfghjklmnpqrstvwxyz",
+	'V': "AEIOU",
+	'v': "aeiou",
+	'H': "0123456789ABCDEF",
+	'h': "0123456789abcdef",
+}
+
+// maxPatternRepeat bounds {n} so a typo cannot allocate gigabytes.
+const maxPatternRepeat = 1024
+
+// PatternError reports an invalid pattern and the 1-based position of the
+// offending character.
+type PatternError struct {
+	Pattern string
+	Pos     int
+	Msg     string
+}
+
+func (e *PatternError) Error() string {
+	return fmt.Sprintf("invalid pattern %q at position %d: %s\n  %s\n  %s^", e.Pattern, e.Pos, e.Msg, e.Pattern, strings.Repeat(" ", e.Pos-1))
+}
+
+// Pattern is a parsed password template such as "LLLL-DDDD-SSSS" or
+// "Cvccvc99!".
+type Pattern struct {
+	elements [][]rune
+}
+
+// ParsePattern parses pattern. classes maps names usable as [name] to the
+// characters they contain.
+func ParsePattern(pattern string, classes map[string]string) (*Pattern, error) {
+	src := []rune(pattern)
+	fail := func(i int, format string, args ...interface{}) error {
+		return &PatternError{Pattern: pattern, Pos: i + 1, Msg: fmt.Sprintf(format, args...)}
+	}
+	p := &Pattern{}
+	// Each class is expanded once, however often the pattern uses it.
+	expanded := map[string][]rune{}
+	for i := 0; i < len(src); i++ {
+		r := src[i]
+		switch {
+		case r == '\\':
+			if i+1 == len(src) {
+				return nil, fail(i, "dangling escape")
+			}
+			i++
+			p.elements = append(p.elements, []rune{src[i]})
+		case r == '[':
+			end := indexRune(src, ']', i)
+			if end < 0 {
+				return nil, fail(i, "unterminated class name")
+			}
+			name := string(src[i+1 : end])
+			chars, ok := classes[name]
+			if !ok {
+				return nil, fail(i+1, "unknown class %q", name)
+			}
+			set, ok := expanded[name]
+			if !ok {
+				var err error
+				if set, err = ParseCharset(chars); err != nil {
+					return nil, fail(i+1, "class %q: %v", name, err)
+				}
+				if len(set) == 0 {
+					return nil, fail(i+1, "class %q is empty", name)
+				}
+				expanded[name] = set
+			}
+			p.elements = append(p.elements, set)
+			i = end
+		case r == '{':
+			end := indexRune(src, '}', i)
+			if end < 0 {
+				return nil, fail(i, "unterminated repetition")
+			}
+			if len(p.elements) == 0 {
+				return nil, fail(i, "repetition without a preceding element")
+			}
+			n, err := strconv.Atoi(string(src[i+1 : end]))
+			if err != nil || n < 1 || n > maxPatternRepeat {
+				return nil, fail(i+1, "repeat count must be a number between 1 and %d", maxPatternRepeat)
+			}
+			last := p.elements[len(p.elements)-1]
+			for j := 1; j < n; j++ {
+				p.elements = append(p.elements, last)
+			}
+			i = end
+		case r == ']' || r == '}':
+			return nil, fail(i, "unexpected %q (escape it as \\%c)", r, r)
+		default:
+			if set, ok := patternClasses[r]; ok {
+				p.elements = append(p.elements, []rune(set))
+			} else {
+				p.elements = append(p.elements, []rune{r})
+			}
+		}
+	}
+	if len(p.elements) == 0 {
+		return nil, fail(0, "pattern is empty")
+	}
+	return p, nil
+}
+
+// Exclude removes chars from every class in the pattern. Literal characters
+// are kept, since the pattern asked for them explicitly.
+func (p *Pattern) Exclude(chars string) error {
+	exclude := []rune(chars)
+	// Repeated elements share their set; filter each set once.
+	filtered := map[*rune][]rune{}
+	for i, set := range p.elements {
+		if len(set) == 1 {
+			continue
+		}
+		remaining, ok := filtered[&set[0]]
+		if !ok {
+			remaining = SubtractRunes(set, exclude)
+			filtered[&set[0]] = remaining
+		}
+		if len(remaining) == 0 {
+			return fmt.Errorf("excluded characters leave pattern element %d empty", i+1)
+		}
+		p.elements[i] = remaining
+	}
+	return nil
+}
+
+// Len returns the length in characters of passwords from this pattern.
+func (p *Pattern) Len() int {
+	return len(p.elements)
+}
+
+// Entropy returns the exact entropy in bits of passwords from this pattern.
+func (p *Pattern) Entropy() float64 {
+	entropy := 0.0
+	for _, set := range p.elements {
+		entropy += math.Log2(float64(len(set)))
+	}
+	return entropy
+}
+
+// Generate returns a password matching the pattern, drawn from random
+// (crypto/rand when nil).
+func (p *Pattern) Generate(random io.Reader) (string, error) {
+	out := make([]rune, len(p.elements))
+	for i, set := range p.elements {
+		n, err := randomIndex(random, len(set))
+		if err != nil {
+			return "", err
+		}
+		out[i] = set[n]
+	}
+	return string(out), nil
+}
+
+func indexRune(src []rune, r rune, from int) int {
+	for i := from; i < len(src); i++ {
+		if src[i] == r {
+			return i
+		}
+	}
+	return -1
+}
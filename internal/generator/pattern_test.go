@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParsePatternErrors(t *testing.T) {
+	classes := map[string]string{"hex": "0-9a-f", "none": "", "bad": "z-a"}
+	tests := []struct {
+		pattern string
+		pos     int
+		msg     string
+	}{
+		{"", 1, "pattern is empty"},
+		{`LLLL\`, 5, "dangling escape"},
+		{"LL[hex", 3, "unterminated class name"},
+		{"D[oct]", 3, `unknown class "oct"`},
+		{"[none]", 2, `class "none" is empty`},
+		{"Dd[bad]", 4, `class "bad": `},
+		{"L{3", 2, "unterminated repetition"},
+		{"{2}L", 1, "repetition without a preceding element"},
+		{"L{0}", 3, "repeat count must be a number between 1 and 1024"},
+		{"L{1025}", 3, "repeat count must be a number between 1 and 1024"},
+		{"L{x}", 3, "repeat count must be a number between 1 and 1024"},
+		{"LL]", 3, `unexpected ']' (escape it as \])`},
+		{"ünï}", 4, `unexpected '}' (escape it as \})`},
+	}
+	for _, tt := range tests {
+		_, err := ParsePattern(tt.pattern, classes)
+		var perr *PatternError
+		if !errors.As(err, &perr) {
+			t.Errorf("ParsePattern(%q) = %v, want a *PatternError", tt.pattern, err)
+			continue
+		}
+		if perr.Pos != tt.pos || !strings.HasPrefix(perr.Msg, tt.msg) {
+			t.Errorf("ParsePattern(%q) error at %d: %q, want %d: %q", tt.pattern, perr.Pos, perr.Msg, tt.pos, tt.msg)
+		}
+	}
+}
+
+func TestPatternErrorCaret(t *testing.T) {
+	_, err := ParsePattern("Cvc[word]", nil)
+	want := "invalid pattern \"Cvc[word]\" at position 5: unknown class \"word\"\n  Cvc[word]\n      ^"
+	if err == nil || err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
+
+func TestParsePattern(t *testing.T) {
+	classes := map[string]string{"hex": "0-9a-f", "ab": "ab"}
+	tests := []struct {
+		pattern string
+		length  int
+		entropy float64 // bits
+	}{
+		{"LLLL-DDDD", 9, 4*math.Log2(52) + 4*math.Log2(10)},
+		{"Cvccvc99!", 9, 4*math.Log2(21) + 2*math.Log2(5)},
+		{"D{4}", 4, 4 * math.Log2(10)},
+		{"[hex]{8}", 8, 8 * 4},
+		{"[ab][ab]-X", 4, 2 + math.Log2(62+27)},
+		{`\L\{\}\[`, 4, 0},
+		{"ünï", 3, 0},
+		{"S{1024}", 1024, 1024 * math.Log2(27)},
+	}
+	for _, tt := range tests {
+		p, err := ParsePattern(tt.pattern, classes)
+		if err != nil {
+			t.Errorf("ParsePattern(%q): %v", tt.pattern, err)
+			continue
+		}
+		if p.Len() != tt.length {
+			t.Errorf("ParsePattern(%q).Len() = %d, want %d", tt.pattern, p.Len(), tt.length)
+		}
+		if got := p.Entropy(); math.Abs(got-tt.entropy) > 1e-9 {
+			t.Errorf("ParsePattern(%q).Entropy() = %v, want %v", tt.pattern, got, tt.entropy)
+		}
+	}
+}
+
+func TestPatternExclude(t *testing.T) {
+	p, err := ParsePattern("DD-h", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Exclude("0123456789-"); err == nil || err.Error() != "excluded characters leave pattern element 1 empty" {
+		t.Errorf("Exclude = %v", err)
+	}
+
+	p, err = ParsePattern("h{4}-", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Exclude("abcdef-"); err != nil {
+		t.Fatal(err)
+	}
+	pw, err := p.Generate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The literal '-' is kept; the classes lose a-f.
+	if len(pw) != 5 || strings.ContainsAny(pw[:4], "abcdef-") || pw[4] != '-' {
+		t.Errorf("Generate = %q", pw)
+	}
+}
@@ -0,0 +1,188 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// PIN lengths outside this range are rejected: shorter PINs are trivially
+// guessable and longer ones make the weak-pattern set expensive to enumerate.
+const (
+	MinPINLength = 4
+	MaxPINLength = 10
+)
+
+// PinRules selects which weak patterns GeneratePIN rejects.
+type PinRules struct {
+	Sequences bool // ascending or descending runs such as 1234 or 9876
+	Repeats   bool // one repeated digit or block such as 0000 or 1212
+	Dates     bool // MMDD, DDMM, YYYY and the 6/8 digit date layouts
+	Blocklist bool // the built-in list of most common PINs
+}
+
+// AllPinRules enables every weak-pattern filter.
+var AllPinRules = PinRules{Sequences: true, Repeats: true, Dates: true, Blocklist: true}
+
+type PinConfig struct {
+	Length int
+	Rules  PinRules
+	// Rand is the source of randomness; nil means crypto/rand.
+	Rand io.Reader
+}
+
+// commonPINs are the most frequently chosen PINs from public breach and
+// survey data, including keypad shapes such as 2580 and 147258.
+var commonPINs = []string{
+	"1234", "1111", "0000", "1212", "7777", "1004", "2000", "4444", "2222", "6969",
+	"9999", "3333", "5555", "6666", "1122", "1313", "8888", "4321", "2001", "1010",
+	"2580", "0852", "1478", "3698", "1357", "2468", "1379", "1590", "1230", "0987",
+	"123456", "654321", "111111", "000000", "121212", "123123", "696969", "112233",
+	"159753", "147258", "258369", "789456", "123321", "666666", "252525", "159357",
+	"147852", "102030", "101010", "131313", "007007", "520520", "123654", "112358",
+	"12345678", "87654321", "11111111", "00000000", "12341234", "11223344", "88888888",
+	"1234567890", "0987654321", "1111111111", "0123456789",
+}
+
+// dateLayouts are the time layouts tried for each PIN length.
+var dateLayouts = map[int][]string{
+	4: {"0102", "0201", "2006"},
+	6: {"020106", "010206", "060102"},
+	8: {"02012006", "01022006", "20060102"},
+}
+
+// GeneratePIN returns a random numeric PIN that passes the selected rules.
+func GeneratePIN(config PinConfig) (string, error) {
+	if config.Length < MinPINLength || config.Length > MaxPINLength {
+		return "", fmt.Errorf("PIN length must be between %d and %d", MinPINLength, MaxPINLength)
+	}
+	for attempt := 0; attempt < 1000; attempt++ {
+		pin := make([]byte, config.Length)
+		for i := range pin {
+			n, err := randomIndex(config.Rand, len(digitChars))
+			if err != nil {
+				return "", err
+			}
+			pin[i] = digitChars[n]
+		}
+		if _, weak := IsWeakPIN(string(pin), config.Rules); !weak {
+			return string(pin), nil
+		}
+	}
+	return "", errors.New("could not generate a PIN satisfying the rules")
+}
+
+// IsWeakPIN reports whether pin matches one of the enabled weak patterns and
+// names the first rule it broke.
+func IsWeakPIN(pin string, rules PinRules) (string, bool) {
+	if rules.Blocklist {
+		for _, common := range commonPINs {
+			if pin == common {
+				return "common PIN", true
+			}
+		}
+	}
+	if rules.Repeats && isPeriodic(pin) {
+		return "repeated digits", true
+	}
+	if rules.Sequences && isSequence(pin) {
+		return "sequence", true
+	}
+	if rules.Dates && isDate(pin) {
+		return "date", true
+	}
+	return "", false
+}
+
+// PINEntropy returns the effective entropy in bits of GeneratePIN's output,
+// i.e. log2 of the number of PINs that survive the filters.
+func PINEntropy(config PinConfig) float64 {
+	weak := map[string]bool{}
+	add := func(pin string) {
+		if _, bad := IsWeakPIN(pin, config.Rules); bad {
+			weak[pin] = true
+		}
+	}
+	n := config.Length
+	if config.Rules.Blocklist {
+		for _, pin := range commonPINs {
+			if len(pin) == n {
+				add(pin)
+			}
+		}
+	}
+	if config.Rules.Repeats {
+		for period := 1; period < n; period++ {
+			if n%period != 0 {
+				continue
+			}
+			limit := int(math.Pow10(period))
+			for block := 0; block < limit; block++ {
+				add(strings.Repeat(fmt.Sprintf("%0*d", period, block), n/period))
+			}
+		}
+	}
+	if config.Rules.Sequences {
+		for start := 0; start+n <= 10; start++ {
+			var up, down []byte
+			for i := 0; i < n; i++ {
+				up = append(up, byte('0'+start+i))
+				down = append(down, byte('9'-start-i))
+			}
+			add(string(up))
+			add(string(down))
+		}
+	}
+	if config.Rules.Dates {
+		for _, layout := range dateLayouts[n] {
+			for d := time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC); d.Year() < 2100; d = d.AddDate(0, 0, 1) {
+				add(d.Format(layout))
+			}
+		}
+	}
+	total := math.Pow10(n)
+	return math.Log2(total - float64(len(weak)))
+}
+
+// isPeriodic reports whether pin is one block repeated, e.g. 0000 or 123123.
+func isPeriodic(pin string) bool {
+	n := len(pin)
+	for period := 1; period < n; period++ {
+		if n%period == 0 && strings.Repeat(pin[:period], n/period) == pin {
+			return true
+		}
+	}
+	return false
+}
+
+// isSequence reports whether each digit is one more (or less) than the last.
+func isSequence(pin string) bool {
+	up, down := true, true
+	for i := 1; i < len(pin); i++ {
+		if pin[i] != pin[i-1]+1 {
+			up = false
+		}
+		if pin[i] != pin[i-1]-1 {
+			down = false
+		}
+	}
+	return up || down
+}
+
+// isDate reports whether pin parses as a date between 1900 and 2099 in one of
+// the layouts for its length.
+func isDate(pin string) bool {
+	for _, layout := range dateLayouts[len(pin)] {
+		t, err := time.Parse(layout, pin)
+		if err != nil {
+			continue
+		}
+		if !strings.Contains(layout, "06") || (t.Year() >= 1900 && t.Year() < 2100) {
+			return true
+		}
+	}
+	return false
+}
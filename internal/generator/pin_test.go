@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestIsWeakPIN(t *testing.T) {
+	tests := []struct {
+		pin  string
+		rule string // empty for a PIN that must pass
+	}{
+		{"1234", "common PIN"},
+		{"2580", "common PIN"},
+		{"147258", "common PIN"},
+		{"0000000", "repeated digits"},
+		{"3939", "repeated digits"},
+		{"407407", "repeated digits"},
+		{"45454545", "repeated digits"},
+		{"3456", "sequence"},
+		{"76543", "sequence"},
+		{"0123456", "sequence"},
+		{"1231", "date"}, // MMDD, 31 December
+		{"3112", "date"}, // DDMM
+		{"1987", "date"}, // YYYY
+		{"250719", "date"},
+		{"19870725", "date"},
+		{"07251987", "date"},
+		{"25071987", "date"},
+		{"4829", ""},
+		{"1399", ""}, // no 13th month, 99th day or year 1399
+		{"3232", "repeated digits"},
+		{"3290", ""},
+		{"58203", ""},
+		{"98761", ""},      // a broken descending run
+		{"13579", ""},      // steps of two are not a sequence
+		{"20251332", ""},   // month 13
+		{"8641975310", ""}, // no rule applies at length 10
+	}
+	for _, tt := range tests {
+		rule, weak := IsWeakPIN(tt.pin, AllPinRules)
+		if rule != tt.rule || weak != (tt.rule != "") {
+			t.Errorf("IsWeakPIN(%q) = %q, %v, want %q", tt.pin, rule, weak, tt.rule)
+		}
+	}
+}
+
+func TestIsWeakPINRules(t *testing.T) {
+	// Each rule only rejects its own patterns.
+	tests := []struct {
+		pin   string
+		rules PinRules
+		weak  bool
+	}{
+		{"1111", PinRules{Repeats: true}, true},
+		{"1111", PinRules{Sequences: true}, false},
+		{"4567", PinRules{Sequences: true}, true},
+		{"4567", PinRules{Repeats: true, Dates: true}, false},
+		{"0214", PinRules{Dates: true}, true},
+		{"0214", PinRules{Sequences: true, Repeats: true}, false},
+		{"6969", PinRules{Blocklist: true}, true},
+		{"1357", PinRules{Blocklist: true}, true},
+		{"1357", PinRules{}, false},
+	}
+	for _, tt := range tests {
+		if _, weak := IsWeakPIN(tt.pin, tt.rules); weak != tt.weak {
+			t.Errorf("IsWeakPIN(%q, %+v) = %v, want %v", tt.pin, tt.rules, weak, tt.weak)
+		}
+	}
+}
+
+// TestPINEntropy checks PINEntropy against a count of every PIN of the
+// length that IsWeakPIN accepts.
+func TestPINEntropy(t *testing.T) {
+	ruleSets := []PinRules{
+		{},
+		{Sequences: true},
+		{Repeats: true},
+		{Dates: true},
+		{Blocklist: true},
+		AllPinRules,
+	}
+	for length := MinPINLength; length <= 6; length++ {
+		for _, rules := range ruleSets {
+			if length == 6 && rules != AllPinRules {
+				continue // a million PINs per rule set is slow; check the default
+			}
+			config := PinConfig{Length: length, Rules: rules}
+			want := math.Log2(float64(countStrongPINs(length, rules)))
+			if got := PINEntropy(config); math.Abs(got-want) > 1e-9 {
+				t.Errorf("PINEntropy(%d, %+v) = %v, want %v", length, rules, got, want)
+			}
+		}
+	}
+}
+
+func countStrongPINs(length int, rules PinRules) int {
+	n := 0
+	limit := int(math.Pow10(length))
+	for i := 0; i < limit; i++ {
+		if _, weak := IsWeakPIN(fmt.Sprintf("%0*d", length, i), rules); !weak {
+			n++
+		}
+	}
+	return n
+}
+
+func TestGeneratePIN(t *testing.T) {
+	for length := MinPINLength; length <= MaxPINLength; length++ {
+		pin, err := GeneratePIN(PinConfig{Length: length, Rules: AllPinRules})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pin) != length {
+			t.Errorf("GeneratePIN(%d) = %q", length, pin)
+		}
+		if rule, weak := IsWeakPIN(pin, AllPinRules); weak {
+			t.Errorf("GeneratePIN(%d) = %q, a %s", length, pin, rule)
+		}
+	}
+	for _, length := range []int{MinPINLength - 1, MaxPINLength + 1} {
+		if _, err := GeneratePIN(PinConfig{Length: length}); err == nil {
+			t.Errorf("GeneratePIN(%d) succeeded", length)
+		}
+	}
+}
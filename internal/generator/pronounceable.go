@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strings"
+)
+
+// Pronounceable passwords are built from consonant-vowel syllables (in the
+// spirit of FIPS-181 and koremutake) using only letters that are hard to
+// mishear when spelled out over the phone.
+const (
+	phoneConsonants = "bdfghjklmnprstvwz"
+	phoneVowels     = "aeiou"
+	phoneSpecials   = "!@#$%&*-+=?"
+)
+
+type PronounceableConfig struct {
+	Length          int
+	IncludeUpper    bool
+	IncludeLower    bool
+	IncludeDigits   bool
+	IncludeSpecials bool
+	// ExcludeChars are removed from the letters, in the case they are
+	// written in, and from the digits and specials used.
+	ExcludeChars string
+	// Rand is the source of randomness; nil means crypto/rand.
+	Rand io.Reader
+}
+
+// GeneratePronounceable returns a pronounceable password and its exact
+// entropy in bits. Every selected class appears at least once: one syllable
+// is capitalised when both cases are selected, and a digit and/or special
+// character is appended after the syllables.
+func GeneratePronounceable(config PronounceableConfig) (string, float64, error) {
+	if !config.IncludeUpper && !config.IncludeLower {
+		return "", 0, errors.New("pronounceable passwords need uppercase or lowercase letters")
+	}
+	letters := config.Length
+	if config.IncludeDigits {
+		letters--
+	}
+	if config.IncludeSpecials {
+		letters--
+	}
+	if letters < 2 {
+		return "", 0, errors.New("length too short for a pronounceable password")
+	}
+	consonants := removeChars(phoneConsonants, config.ExcludeChars)
+	vowels := removeChars(phoneVowels, config.ExcludeChars)
+	upperConsonants := removeChars(strings.ToUpper(phoneConsonants), config.ExcludeChars)
+	digits := removeChars(digitChars, config.ExcludeChars)
+	specials := removeChars(phoneSpecials, config.ExcludeChars)
+	if !config.IncludeLower {
+		// Every letter is upper case, so only upper case exclusions apply.
+		consonants = upperConsonants
+		vowels = removeChars(strings.ToUpper(phoneVowels), config.ExcludeChars)
+	}
+	switch {
+	case config.IncludeUpper && upperConsonants == "":
+		return "", 0, errors.New("excluded characters leave no uppercase letter for a pronounceable password")
+	case config.IncludeDigits && digits == "":
+		return "", 0, errors.New("excluded characters leave no digit for a pronounceable password")
+	case consonants == "" || vowels == "" || (config.IncludeSpecials && specials == ""):
+		return "", 0, errors.New("excluded characters leave nothing to build a pronounceable password from")
+	}
+
+	entropy := 0.0
+	// With both cases the consonant of one syllable is drawn upper case.
+	capital := -1
+	if syllables := letters / 2; config.IncludeUpper && config.IncludeLower {
+		n, err := randomIndex(config.Rand, syllables)
+		if err != nil {
+			return "", 0, err
+		}
+		entropy += math.Log2(float64(syllables))
+		capital = n * 2
+	}
+	word := make([]byte, 0, config.Length)
+	for i := 0; i < letters; i++ {
+		set := vowels
+		switch {
+		case i == capital:
+			set = upperConsonants
+		case i%2 == 0:
+			set = consonants
+		}
+		c, err := pick(config.Rand, set, &entropy)
+		if err != nil {
+			return "", 0, err
+		}
+		word = append(word, c)
+	}
+	if config.IncludeDigits {
+		d, err := pick(config.Rand, digits, &entropy)
+		if err != nil {
+			return "", 0, err
+		}
+		word = append(word, d)
+	}
+	if config.IncludeSpecials {
+		s, err := pick(config.Rand, specials, &entropy)
+		if err != nil {
+			return "", 0, err
+		}
+		word = append(word, s)
+	}
+	return string(word), entropy, nil
+}
+
+// pick draws one byte from set and adds the choice's entropy to total.
+func pick(random io.Reader, set string, total *float64) (byte, error) {
+	i, err := randomIndex(random, len(set))
+	if err != nil {
+		return 0, err
+	}
+	*total += math.Log2(float64(len(set)))
+	return set[i], nil
+}
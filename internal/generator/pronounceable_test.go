@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPronounceableExclusions(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  PronounceableConfig
+		exclude string
+	}{
+		{"similar, both cases", PronounceableConfig{Length: 12, IncludeUpper: true, IncludeLower: true, IncludeDigits: true}, ExclusionProfiles["similar"]},
+		{"similar, upper case", PronounceableConfig{Length: 12, IncludeUpper: true, IncludeDigits: true}, ExclusionProfiles["similar"]},
+		{"upper consonants", PronounceableConfig{Length: 10, IncludeUpper: true, IncludeLower: true}, "BDFGHJKLMN"},
+		{"digits", PronounceableConfig{Length: 10, IncludeLower: true, IncludeDigits: true, IncludeSpecials: true}, "012345678!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.ExcludeChars = tt.exclude
+			for range 500 {
+				pw, _, err := GeneratePronounceable(tt.config)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if i := strings.IndexAny(pw, tt.exclude); i >= 0 {
+					t.Fatalf("%q contains excluded %q", pw, pw[i])
+				}
+				if len(pw) != tt.config.Length {
+					t.Fatalf("%q has length %d, want %d", pw, len(pw), tt.config.Length)
+				}
+			}
+		})
+	}
+}
+
+func TestPronounceableExclusionErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config PronounceableConfig
+		want   string
+	}{
+		{"no digit", PronounceableConfig{Length: 10, IncludeLower: true, IncludeDigits: true, ExcludeChars: digitChars}, "no digit"},
+		{"no capital", PronounceableConfig{Length: 10, IncludeUpper: true, IncludeLower: true, ExcludeChars: strings.ToUpper(phoneConsonants)}, "no uppercase letter"},
+		{"no upper vowel", PronounceableConfig{Length: 10, IncludeUpper: true, ExcludeChars: "AEIOU"}, "leave nothing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := GeneratePronounceable(tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPronounceableEntropy(t *testing.T) {
+	// 3 syllables and a digit with L and 0 excluded: the capital's position,
+	// an upper case consonant, two lower case ones, three vowels and a digit.
+	config := PronounceableConfig{Length: 7, IncludeUpper: true, IncludeLower: true, IncludeDigits: true, ExcludeChars: "L0"}
+	_, entropy, err := GeneratePronounceable(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := math.Log2(3) + math.Log2(16) + 2*math.Log2(17) + 3*math.Log2(5) + math.Log2(9)
+	if math.Abs(entropy-want) > 1e-9 {
+		t.Errorf("entropy = %v, want %v", entropy, want)
+	}
+}
@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// randomSource returns r, or the package CSPRNG reader when r is nil.
+// Callers that pass their own reader, such as a buffered crypto/rand
+// stream per goroutine, must not share it between goroutines.
+func randomSource(r io.Reader) io.Reader {
+	if r == nil {
+		return reader
+	}
+	return r
+}
+
+// randomIndex returns a uniformly distributed integer in [0, n) drawn from
+// r, or the package CSPRNG reader when r is nil. Values from the
+// incomplete top interval of the uint64 range are rejected so every index
+// is equally likely; for the small n used here that almost never costs a
+// second read.
+func randomIndex(r io.Reader, n int) (int, error) {
+	r = randomSource(r)
+	bound := uint64(n)
+	// limit is the largest multiple of bound, minus one, that fits.
+	limit := ^uint64(0) - (^uint64(0)%bound+1)%bound
+	var b [8]byte
+	for {
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return 0, err
+		}
+		if v := binary.LittleEndian.Uint64(b[:]); v <= limit {
+			return int(v % bound), nil
+		}
+	}
+}
+
+// randomIndexes fills idx with independent randomIndex(r, n) values using
+// a single read for the whole slice, which matters when generating
+// millions of passwords.
+func randomIndexes(r io.Reader, n int, idx []int) error {
+	r = randomSource(r)
+	bound := uint64(n)
+	limit := ^uint64(0) - (^uint64(0)%bound+1)%bound
+	buf := make([]byte, 8*len(idx))
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+	for i := range idx {
+		v := binary.LittleEndian.Uint64(buf[8*i:])
+		if v > limit {
+			var err error
+			if idx[i], err = randomIndex(r, n); err != nil {
+				return err
+			}
+			continue
+		}
+		idx[i] = int(v % bound)
+	}
+	return nil
+}
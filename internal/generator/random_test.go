@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"bufio"
+	"crypto/rand"
+	"testing"
+)
+
+// benchLength is the password length of the generation benchmarks.
+const benchLength = 16
+
+func benchCharset() string {
+	return PasswordCharset(PasswordConfig{IncludeUpper: true, IncludeLower: true, IncludeDigits: true, IncludeSpecials: true})
+}
+
+// randomUint8 is the original generator's draw: one crypto/rand read per
+// character, reduced modulo the charset size. It is kept here only as the
+// baseline for BenchmarkPasswords.
+func randomUint8() (uint8, error) {
+	b := make([]byte, 1)
+	_, err := reader.Read(b)
+	return b[0], err
+}
+
+// BenchmarkPasswords compares drawing 16-character passwords one byte per
+// read with the batched uint64 draws, unbuffered and from the buffered
+// per-worker stream that generate --workers uses.
+func BenchmarkPasswords(b *testing.B) {
+	charset := benchCharset()
+	b.Run("randomUint8", func(b *testing.B) {
+		b.SetBytes(benchLength)
+		for range b.N {
+			password := make([]byte, benchLength)
+			for j := range password {
+				num, err := randomUint8()
+				if err != nil {
+					b.Fatal(err)
+				}
+				password[j] = charset[num%uint8(len(charset))]
+			}
+			_ = string(password)
+		}
+	})
+	b.Run("randomIndexes", func(b *testing.B) {
+		benchGeneratePasswords(b, PasswordConfig{})
+	})
+	b.Run("randomIndexes-buffered", func(b *testing.B) {
+		benchGeneratePasswords(b, PasswordConfig{Rand: bufio.NewReaderSize(rand.Reader, 4096)})
+	})
+}
+
+func benchGeneratePasswords(b *testing.B, config PasswordConfig) {
+	config.Length, config.Count = benchLength, 1
+	config.IncludeUpper, config.IncludeLower = true, true
+	config.IncludeDigits, config.IncludeSpecials = true, true
+	b.SetBytes(benchLength)
+	for range b.N {
+		if _, err := GeneratePasswords(config); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRandomIndexes(b *testing.B) {
+	n := len(benchCharset())
+	idx := make([]int, benchLength)
+	random := bufio.NewReaderSize(rand.Reader, 4096)
+	b.SetBytes(benchLength)
+	for range b.N {
+		if err := randomIndexes(random, n, idx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,12 +1,19 @@
 package generator
 
 import (
+	"fmt"
 	"math"
 	"strings"
 )
 
 // CheckPasswordStrength returns entropy and suggestions in addition to strength
 func CheckPasswordStrength(password string) (string, float64, []string) {
+	return CheckPasswordStrengthWith(password, nil)
+}
+
+// CheckPasswordStrengthWith is CheckPasswordStrength that also rates a
+// password containing a word from banned as Weak.
+func CheckPasswordStrengthWith(password string, banned *Blocklist) (string, float64, []string) {
 	var score int
 	var suggestions []string
 	entropy := 0.0
@@ -55,6 +62,12 @@ func CheckPasswordStrength(password string) (string, float64, []string) {
 		entropy = float64(len(password)) * math.Log2(float64(pool))
 	}
 
+	word, isBanned := banned.Match(password)
+	if isBanned {
+		score = 0
+		suggestions = append(suggestions, fmt.Sprintf("Avoid the banned word %q, including l33t spellings.", word))
+	}
+
 	strength := "Unknown"
 	switch score {
 	case 0, 1:
@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Target describes a context a secret is pasted into. Exclude is removed
+// from the charset so the raw secret is safe there; Escape renders the
+// secret as a literal for that context.
+type Target struct {
+	Exclude string
+	Escape  func(secret string) string
+}
+
+// targetWhitespace ends or splits unquoted values in every target that
+// excludes characters rather than escaping them. Custom charsets can
+// contain it.
+const targetWhitespace = " \t\n\r"
+
+// Targets are the contexts supported by --target.
+var Targets = map[string]Target{
+	// .env files: unquoted values end at whitespace or '#', and the files
+	// are often sourced by shells, so shell metacharacters are excluded too.
+	"env": {Exclude: ExclusionProfiles["shell"] + targetWhitespace},
+	// Kubernetes manifests: any character, emitted as a YAML double-quoted
+	// scalar for stringData.
+	"k8s": {Escape: quoteJSON},
+	// URLs and connection strings (JDBC, DSNs): only RFC 3986 unreserved
+	// characters, so the secret needs no percent-encoding in userinfo.
+	"url": {Exclude: ExclusionProfiles["url"] + targetWhitespace},
+	// POSIX shells and Windows cmd scripts: no metacharacters at all, since
+	// the two quote in incompatible ways.
+	"shell": {Exclude: ExclusionProfiles["shell"] + targetWhitespace},
+	// JSON documents: any character, emitted as a JSON string literal.
+	"json": {Escape: quoteJSON},
+	// XML documents: any character, emitted with entities for markup.
+	"xml": {Escape: escapeXML},
+	// LDAP DNs, search filters and LDIF: none of the characters RFC 4514
+	// and RFC 4515 require escaping, no '#' or space that would need it at
+	// the start or end of a DN value, and nothing that breaks an LDIF line.
+	"ldap": {Exclude: "\\*()\x00,+\"<>;=#:" + targetWhitespace},
+}
+
+// TargetNames returns the supported target names in sorted order.
+func TargetNames() []string {
+	var names []string
+	for name := range Targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupTarget returns the named target; an empty name is the zero Target.
+func LookupTarget(name string) (Target, error) {
+	if name == "" {
+		return Target{}, nil
+	}
+	t, ok := Targets[strings.ToLower(name)]
+	if !ok {
+		return Target{}, fmt.Errorf("unknown target %q (want one of %s)", name, strings.Join(TargetNames(), ", "))
+	}
+	return t, nil
+}
+
+// Render returns secret as it should be written for the target.
+func (t Target) Render(secret string) string {
+	if t.Escape == nil {
+		return secret
+	}
+	return t.Escape(secret)
+}
+
+func quoteJSON(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
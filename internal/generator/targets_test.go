@@ -0,0 +1,246 @@
+package generator
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// escapeSamples are secrets for the targets that escape rather than
+// exclude: every special character plus quotes, markup, whitespace and
+// non-ASCII text that custom charsets can produce.
+var escapeSamples = []string{
+	specialChars,
+	`"quoted" 'single' \back\slash`,
+	"<tag attr='x'>&amp;</tag>",
+	"tab\there",
+	"héllo wörld ✓   末",
+	"]]>--><!--",
+}
+
+// excludeSamples returns secrets drawn from every class with the target's
+// characters excluded: the whole charset in one string, and random ones.
+func excludeSamples(t *testing.T, target Target) []string {
+	t.Helper()
+	config := PasswordConfig{
+		Length:          64,
+		Count:           20,
+		IncludeUpper:    true,
+		IncludeLower:    true,
+		IncludeDigits:   true,
+		IncludeSpecials: true,
+		ExcludeChars:    target.Exclude,
+	}
+	passwords, err := GeneratePasswords(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append([]string{PasswordCharset(config)}, passwords...)
+}
+
+func TestTargetJSON(t *testing.T) {
+	for _, secret := range escapeSamples {
+		var got string
+		rendered := Targets["json"].Render(secret)
+		if err := json.Unmarshal([]byte(rendered), &got); err != nil {
+			t.Fatalf("json.Unmarshal(%s): %v", rendered, err)
+		}
+		if got != secret {
+			t.Errorf("json round trip of %q = %q", secret, got)
+		}
+	}
+}
+
+func TestTargetXML(t *testing.T) {
+	for _, secret := range escapeSamples {
+		var doc struct {
+			Value string `xml:",chardata"`
+		}
+		rendered := Targets["xml"].Render(secret)
+		if err := xml.Unmarshal([]byte("<password>"+rendered+"</password>"), &doc); err != nil {
+			t.Fatalf("xml.Unmarshal(%s): %v", rendered, err)
+		}
+		if doc.Value != secret {
+			t.Errorf("xml round trip of %q = %q", secret, doc.Value)
+		}
+	}
+}
+
+func TestTargetK8s(t *testing.T) {
+	for _, secret := range escapeSamples {
+		var manifest struct {
+			StringData map[string]string `yaml:"stringData"`
+		}
+		rendered := Targets["k8s"].Render(secret)
+		doc := "apiVersion: v1\nkind: Secret\nstringData:\n  password: " + rendered + "\n"
+		if err := yaml.Unmarshal([]byte(doc), &manifest); err != nil {
+			t.Fatalf("yaml.Unmarshal(%s): %v", rendered, err)
+		}
+		if got := manifest.StringData["password"]; got != secret {
+			t.Errorf("k8s round trip of %q = %q", secret, got)
+		}
+	}
+}
+
+func TestTargetURL(t *testing.T) {
+	for _, secret := range excludeSamples(t, Targets["url"]) {
+		rendered := Targets["url"].Render(secret)
+		u, err := url.Parse("postgres://app:" + rendered + "@db.example.com:5432/app")
+		if err != nil {
+			t.Fatalf("url.Parse with %q: %v", rendered, err)
+		}
+		if got, _ := u.User.Password(); got != secret || u.Host != "db.example.com:5432" {
+			t.Errorf("url round trip of %q = %q (host %q)", secret, got, u.Host)
+		}
+		// No percent-encoding needed: the userinfo is written as is.
+		if got := url.UserPassword("app", secret).String(); got != "app:"+secret {
+			t.Errorf("userinfo of %q is encoded as %q", secret, got)
+		}
+	}
+}
+
+func TestTargetShell(t *testing.T) {
+	sh := lookShell(t)
+	for _, secret := range excludeSamples(t, Targets["shell"]) {
+		rendered := Targets["shell"].Render(secret)
+		out, err := exec.Command(sh, "-c", "printf '%s' "+rendered).Output()
+		if err != nil {
+			t.Fatalf("sh with %q: %v", rendered, err)
+		}
+		if string(out) != secret {
+			t.Errorf("shell round trip of %q = %q", secret, out)
+		}
+	}
+}
+
+func TestTargetEnv(t *testing.T) {
+	sh := lookShell(t)
+	path := filepath.Join(t.TempDir(), ".env")
+	if got, want := "DB_PASSWORD="+Targets["env"].Render("aZ9@-_+:,./"), "DB_PASSWORD=aZ9@-_+:,./"; got != want {
+		t.Errorf(".env line = %q, want %q", got, want)
+	}
+	for _, secret := range append([]string{"aZ9@-_+:,./"}, excludeSamples(t, Targets["env"])...) {
+		env := "# database\nDB_PASSWORD=" + Targets["env"].Render(secret) + " # trailing comment\nDB_USER=app\n"
+		if err := os.WriteFile(path, []byte(env), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		out, err := exec.Command(sh, "-c", `. "$1"; printf '%s' "$DB_PASSWORD"`, "sh", path).Output()
+		if err != nil {
+			t.Fatalf("sourcing .env with %q: %v", secret, err)
+		}
+		if string(out) != secret {
+			t.Errorf("sourced .env round trip of %q = %q", secret, out)
+		}
+	}
+}
+
+// TestTargetCharsets pins the characters each excluding target keeps from
+// every standard class: only these may appear unescaped in its context.
+func TestTargetCharsets(t *testing.T) {
+	const alnum = lowerChars + upperChars + digitChars
+	tests := []struct {
+		target, want string
+	}{
+		// POSIX shell words and dotenv values: no quoting, expansion,
+		// globbing, comments or word splitting.
+		{"shell", alnum + "@-_+:,./"},
+		{"env", alnum + "@-_+:,./"},
+		// RFC 3986 section 2.3 unreserved characters, minus '~'.
+		{"url", alnum + "-_."},
+		// RFC 4514 section 2.4 escapes '"', '+', ',', ';', '<', '>', '\\',
+		// and a leading '#' or space; RFC 4515 section 3 escapes '*', '(',
+		// ')' and '\\'; LDIF values cannot start with ':' or '<'.
+		{"ldap", alnum + "!@$%^&-_[]{}|./?"},
+	}
+	for _, tt := range tests {
+		got := PasswordCharset(PasswordConfig{
+			IncludeUpper: true, IncludeLower: true, IncludeDigits: true, IncludeSpecials: true,
+			ExcludeChars: Targets[tt.target].Exclude,
+		})
+		if got != tt.want {
+			t.Errorf("%s charset = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
+
+// TestTargetExcludes checks that the characters that need quoting or
+// escaping in each context, including those only custom charsets produce,
+// are excluded.
+func TestTargetExcludes(t *testing.T) {
+	tests := []struct {
+		target, unsafe string
+	}{
+		{"shell", "$`\"'\\!&|;<>()*?[]{}#~%^= \t\n\r"},
+		{"env", "$`\"'\\#= \t\n\r"},
+		{"url", ":/?#[]@!$&'()*+,;=%\"<>\\^`{|} \t\n\r"},
+		{"ldap", "\"+,;<>\\#*()\x00:= \t\n\r"},
+	}
+	for _, tt := range tests {
+		for _, r := range tt.unsafe {
+			if !strings.ContainsRune(Targets[tt.target].Exclude, r) {
+				t.Errorf("%s target allows %q", tt.target, r)
+			}
+		}
+	}
+}
+
+// TestTargetLDAP checks the exclusions against the values that the RFC 4514
+// section 4 and RFC 4515 section 4 examples had to escape: none of them can
+// be generated.
+func TestTargetLDAP(t *testing.T) {
+	for _, value := range []string{
+		`James "Jim" Smith, III`, // CN=James \"Jim\" Smith\, III
+		"Before\rAfter",          // CN=Before\0dAfter
+		"#04024869",              // 1.3.6.1.4.1.1466.0=#04024869
+		" leading space",         // \ leading space
+		"Parens R Us (for all your parenthetical needs)", // \28 ... \29
+		"*",                // (cn=*\2A*)
+		`C:\MyFile`,        // (filename=C:\5cMyFile)
+		"\x00\x00\x00\x04", // (bin=\00\00\00\04)
+	} {
+		if !strings.ContainsAny(value, Targets["ldap"].Exclude) {
+			t.Errorf("ldap target could generate %q, which needs escaping", value)
+		}
+	}
+}
+
+// TestTargetEscapes pins the output of the escaping targets for inputs
+// with quotes, backslashes, '$', markup, control characters and non-ASCII
+// text.
+func TestTargetEscapes(t *testing.T) {
+	tests := []struct {
+		target, secret, want string
+	}{
+		{"json", `pa"ss\word`, `"pa\"ss\\word"`},
+		{"json", "$HOME <a&b>", `"$HOME <a&b>"`},
+		{"json", "line\nbreak\ttab", `"line\nbreak\ttab"`},
+		{"json", "#x ünï", `"#x ünï"`},
+		{"k8s", `'single' "double"`, `"'single' \"double\""`},
+		{"k8s", " #lead", `" #lead"`},
+		{"k8s", "back\\slash\r", `"back\\slash\r"`},
+		{"xml", `<a href="x">&'</a>`, "&lt;a href=&#34;x&#34;&gt;&amp;&#39;&lt;/a&gt;"},
+		{"xml", "line\nbreak\ttab", "line&#xA;break&#x9;tab"},
+		{"xml", "$\\ ü", "$\\ ü"},
+	}
+	for _, tt := range tests {
+		if got := Targets[tt.target].Render(tt.secret); got != tt.want {
+			t.Errorf("%s render of %q = %s, want %s", tt.target, tt.secret, got, tt.want)
+		}
+	}
+}
+
+func lookShell(t *testing.T) string {
+	t.Helper()
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no POSIX shell available")
+	}
+	return sh
+}
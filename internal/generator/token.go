@@ -0,0 +1,157 @@
+package generator
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// TokenTypes are the machine-secret formats supported by GenerateToken.
+var TokenTypes = []string{"hex", "base64", "base64url", "base32", "uuidv4", "uuidv7", "apikey"}
+
+const (
+	base62Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// apiKeyChecksumLen is the width of the base62 CRC32 suffix; 62^6 > 2^32.
+	apiKeyChecksumLen = 6
+	// DefaultAPIKeyPrefix is used when no prefix is configured.
+	DefaultAPIKeyPrefix = "pwf"
+)
+
+type TokenConfig struct {
+	Type   string
+	Bytes  int    // random bytes for the encoded types and API keys
+	Prefix string // API key prefix, e.g. "ghp"
+	// Rand is the source of randomness; nil means crypto/rand.
+	Rand io.Reader
+}
+
+// GenerateToken returns a machine secret of the configured type and its
+// entropy in bits.
+func GenerateToken(config TokenConfig) (string, float64, error) {
+	switch config.Type {
+	case "uuidv4":
+		return uuidV4(config.Rand)
+	case "uuidv7":
+		return uuidV7(config.Rand, time.Now())
+	case "apikey":
+		return apiKey(config)
+	}
+	if config.Bytes <= 0 {
+		return "", 0, errors.New("token size must be at least 1 byte")
+	}
+	b := make([]byte, config.Bytes)
+	if _, err := io.ReadFull(randomSource(config.Rand), b); err != nil {
+		return "", 0, err
+	}
+	entropy := float64(8 * config.Bytes)
+	switch config.Type {
+	case "hex":
+		return hex.EncodeToString(b), entropy, nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(b), entropy, nil
+	case "base64url":
+		return base64.RawURLEncoding.EncodeToString(b), entropy, nil
+	case "base32":
+		return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b), entropy, nil
+	}
+	return "", 0, fmt.Errorf("unknown token type %q (want one of %s)", config.Type, strings.Join(TokenTypes, ", "))
+}
+
+// ValidateAPIKey checks the structure and checksum of a key produced by
+// GenerateToken with type apikey and returns its prefix.
+func ValidateAPIKey(key string) (string, error) {
+	sep := strings.LastIndexByte(key, '_')
+	if sep <= 0 {
+		return "", errors.New("missing prefix separator '_'")
+	}
+	prefix, rest := key[:sep], key[sep+1:]
+	if len(rest) <= apiKeyChecksumLen {
+		return "", errors.New("key is too short")
+	}
+	for _, r := range rest {
+		if !strings.ContainsRune(base62Chars, r) {
+			return "", fmt.Errorf("invalid character %q", r)
+		}
+	}
+	body, sum := rest[:len(rest)-apiKeyChecksumLen], rest[len(rest)-apiKeyChecksumLen:]
+	if apiKeyChecksum(prefix, body) != sum {
+		return "", errors.New("checksum mismatch")
+	}
+	return prefix, nil
+}
+
+// apiKey builds prefix_<base62 random><base62 CRC32 of prefix and random>,
+// in the style of GitHub tokens, so typos and truncation can be detected
+// offline.
+func apiKey(config TokenConfig) (string, float64, error) {
+	prefix := config.Prefix
+	if prefix == "" {
+		prefix = DefaultAPIKeyPrefix
+	}
+	if strings.ContainsAny(prefix, "_ ") {
+		return "", 0, errors.New("API key prefix must not contain '_' or spaces")
+	}
+	bytes := config.Bytes
+	if bytes <= 0 {
+		return "", 0, errors.New("token size must be at least 1 byte")
+	}
+	n := int(math.Ceil(float64(8*bytes) / math.Log2(62)))
+	body := make([]byte, n)
+	for i := range body {
+		idx, err := randomIndex(config.Rand, len(base62Chars))
+		if err != nil {
+			return "", 0, err
+		}
+		body[i] = base62Chars[idx]
+	}
+	key := prefix + "_" + string(body) + apiKeyChecksum(prefix, string(body))
+	return key, float64(n) * math.Log2(62), nil
+}
+
+func apiKeyChecksum(prefix, body string) string {
+	sum := crc32.ChecksumIEEE([]byte(prefix + "_" + body))
+	out := make([]byte, apiKeyChecksumLen)
+	for i := apiKeyChecksumLen - 1; i >= 0; i-- {
+		out[i] = base62Chars[sum%62]
+		sum /= 62
+	}
+	return string(out)
+}
+
+func uuidV4(random io.Reader) (string, float64, error) {
+	var u [16]byte
+	if _, err := io.ReadFull(randomSource(random), u[:]); err != nil {
+		return "", 0, err
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return formatUUID(u), 122, nil
+}
+
+// uuidV7 returns a time-ordered UUID (RFC 9562): 48 bits of Unix
+// milliseconds followed by 74 random bits.
+func uuidV7(random io.Reader, now time.Time) (string, float64, error) {
+	var u [16]byte
+	if _, err := io.ReadFull(randomSource(random), u[6:]); err != nil {
+		return "", 0, err
+	}
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(now.UnixMilli()))
+	copy(u[:6], ms[2:])
+	u[6] = u[6]&0x0f | 0x70
+	u[8] = u[8]&0x3f | 0x80
+	return formatUUID(u), 74, nil
+}
+
+func formatUUID(u [16]byte) string {
+	h := hex.EncodeToString(u[:])
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
@@ -0,0 +1,131 @@
+package hasher
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Algorithms are the hash formats supported by Hash.
+var Algorithms = []string{"bcrypt", "argon2id", "scrypt", "sha512crypt", "sha256crypt", "pbkdf2", "scram-sha-256", "apr1"}
+
+// Parameters follow current OWASP password storage recommendations, except
+// where the target format fixes them (SCRAM's 4096 iterations match
+// PostgreSQL's default).
+const (
+	bcryptCost = 12
+
+	argon2Time    = 3
+	argon2Memory  = 64 * 1024 // KiB
+	argon2Threads = 4
+	argon2KeyLen  = 32
+
+	scryptLogN   = 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+
+	pbkdf2Iterations = 600000
+	pbkdf2KeyLen     = 32
+
+	scramIterations = 4096
+
+	saltLen = 16
+)
+
+// b64 is unpadded standard base64, as used by PHC strings.
+var b64 = base64.RawStdEncoding
+
+// Hash returns password hashed with alg in that format's usual string form:
+//
+//	bcrypt         $2a$12$...
+//	argon2id       $argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>
+//	scrypt         $scrypt$ln=15,r=8,p=1$<salt>$<hash>
+//	sha512crypt    $6$<salt>$<hash>        (crypt(3), /etc/shadow)
+//	sha256crypt    $5$<salt>$<hash>
+//	pbkdf2         $pbkdf2-sha256$600000$<salt>$<hash>  (passlib)
+//	scram-sha-256  SCRAM-SHA-256$4096:<salt>$<StoredKey>:<ServerKey>  (PostgreSQL)
+//	apr1           $apr1$<salt>$<hash>     (legacy Apache htpasswd)
+func Hash(alg, password string) (string, error) {
+	pw := []byte(password)
+	switch alg {
+	case "bcrypt":
+		if len(pw) > 72 {
+			return "", fmt.Errorf("bcrypt only uses the first 72 bytes; password is %d bytes", len(pw))
+		}
+		h, err := bcrypt.GenerateFromPassword(pw, bcryptCost)
+		return string(h), err
+	}
+
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	switch alg {
+	case "argon2id":
+		key := argon2.IDKey(pw, salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+			argon2.Version, argon2Memory, argon2Time, argon2Threads, b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+	case "scrypt":
+		key, err := scrypt.Key(pw, salt, 1<<scryptLogN, scryptR, scryptP, scryptKeyLen)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s",
+			scryptLogN, scryptR, scryptP, b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+	case "sha512crypt", "sha256crypt":
+		id := "6"
+		if alg == "sha256crypt" {
+			id = "5"
+		}
+		return shaCrypt(id, pw, cryptSalt(salt), 0), nil
+	case "apr1":
+		return md5Crypt("$apr1$", pw, cryptSalt(salt)), nil
+	case "pbkdf2":
+		key := pbkdf2.Key(pw, salt, pbkdf2Iterations, pbkdf2KeyLen, sha256.New)
+		return fmt.Sprintf("$pbkdf2-sha256$%d$%s$%s", pbkdf2Iterations, ab64(salt), ab64(key)), nil
+	case "scram-sha-256":
+		storedKey, serverKey := scramKeys(pw, salt, scramIterations)
+		return fmt.Sprintf("SCRAM-SHA-256$%d:%s$%s:%s", scramIterations,
+			base64.StdEncoding.EncodeToString(salt),
+			base64.StdEncoding.EncodeToString(storedKey),
+			base64.StdEncoding.EncodeToString(serverKey)), nil
+	}
+	return "", fmt.Errorf("unknown hash algorithm %q (want one of %s)", alg, strings.Join(Algorithms, ", "))
+}
+
+// scramKeys derives the RFC 5802 StoredKey and ServerKey for SHA-256.
+func scramKeys(password, salt []byte, iterations int) (storedKey, serverKey []byte) {
+	salted := pbkdf2.Key(password, salt, iterations, sha256.Size, sha256.New)
+	clientKey := hmacSHA256(salted, "Client Key")
+	stored := sha256.Sum256(clientKey)
+	return stored[:], hmacSHA256(salted, "Server Key")
+}
+
+func hmacSHA256(key []byte, msg string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(msg))
+	return m.Sum(nil)
+}
+
+// cryptSalt maps random bytes onto the crypt(3) salt alphabet.
+func cryptSalt(random []byte) []byte {
+	out := make([]byte, len(random))
+	for i, b := range random {
+		out[i] = cryptAlphabet[b&0x3f]
+	}
+	return out
+}
+
+// ab64 is passlib's "adapted base64": unpadded, with '.' instead of '+'.
+func ab64(b []byte) string {
+	return strings.ReplaceAll(b64.EncodeToString(b), "+", ".")
+}
@@ -0,0 +1,66 @@
+package hasher
+
+import (
+	"crypto/md5"
+	"strings"
+)
+
+// MD5-crypt as used by FreeBSD ($1$) and Apache htpasswd ($apr1$). It is
+// weak and only offered because older htpasswd consumers require it.
+const md5CryptMaxSalt = 8
+
+var md5CryptOrder = [][3]int{{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}}
+
+func md5Crypt(magic string, password, salt []byte) string {
+	if len(salt) > md5CryptMaxSalt {
+		salt = salt[:md5CryptMaxSalt]
+	}
+	alt := md5.Sum(append(append(append([]byte{}, password...), salt...), password...))
+
+	h := md5.New()
+	h.Write(password)
+	h.Write([]byte(magic))
+	h.Write(salt)
+	for n := len(password); n > 0; n -= 16 {
+		h.Write(alt[:min(n, 16)])
+	}
+	for n := len(password); n > 0; n >>= 1 {
+		if n&1 != 0 {
+			h.Write([]byte{0})
+		} else {
+			h.Write(password[:1])
+		}
+	}
+	final := h.Sum(nil)
+
+	for i := 0; i < 1000; i++ {
+		h := md5.New()
+		if i&1 != 0 {
+			h.Write(password)
+		} else {
+			h.Write(final)
+		}
+		if i%3 != 0 {
+			h.Write(salt)
+		}
+		if i%7 != 0 {
+			h.Write(password)
+		}
+		if i&1 != 0 {
+			h.Write(final)
+		} else {
+			h.Write(password)
+		}
+		final = h.Sum(nil)
+	}
+
+	var out strings.Builder
+	out.WriteString(magic)
+	out.Write(salt)
+	out.WriteByte('$')
+	for _, g := range md5CryptOrder {
+		encode24(&out, final[g[0]], final[g[1]], final[g[2]], 4)
+	}
+	encode24(&out, 0, 0, final[11], 2)
+	return out.String()
+}
@@ -0,0 +1,156 @@
+package hasher
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// SHA-crypt ($5$ and $6$) as specified by Ulrich Drepper, used by glibc
+// crypt(3) and /etc/shadow.
+const (
+	shaCryptDefaultRounds = 5000
+	shaCryptMinRounds     = 1000
+	shaCryptMaxRounds     = 999999999
+	shaCryptMaxSalt       = 16
+)
+
+// cryptAlphabet is the base64 variant used by crypt(3).
+const cryptAlphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Byte orders in which the final digest is encoded, three bytes at a time.
+var (
+	sha256CryptOrder = [][3]int{
+		{0, 10, 20}, {21, 1, 11}, {12, 22, 2}, {3, 13, 23}, {24, 4, 14},
+		{15, 25, 5}, {6, 16, 26}, {27, 7, 17}, {18, 28, 8}, {9, 19, 29},
+	}
+	sha512CryptOrder = [][3]int{
+		{0, 21, 42}, {22, 43, 1}, {44, 2, 23}, {3, 24, 45}, {25, 46, 4},
+		{47, 5, 26}, {6, 27, 48}, {28, 49, 7}, {50, 8, 29}, {9, 30, 51},
+		{31, 52, 10}, {53, 11, 32}, {12, 33, 54}, {34, 55, 13}, {56, 14, 35},
+		{15, 36, 57}, {37, 58, 16}, {59, 17, 38}, {18, 39, 60}, {40, 61, 19},
+		{62, 20, 41},
+	}
+)
+
+// shaCrypt computes a $5$ or $6$ hash. rounds of 0 means the default, which
+// is then omitted from the output as crypt(3) does.
+func shaCrypt(id string, password, salt []byte, rounds int) string {
+	newHash := sha512.New
+	if id == "5" {
+		newHash = sha256.New
+	}
+	customRounds := rounds != 0
+	if !customRounds {
+		rounds = shaCryptDefaultRounds
+	}
+	rounds = min(max(rounds, shaCryptMinRounds), shaCryptMaxRounds)
+	if len(salt) > shaCryptMaxSalt {
+		salt = salt[:shaCryptMaxSalt]
+	}
+
+	b := sum(newHash, password, salt, password)
+	h := newHash()
+	h.Write(password)
+	h.Write(salt)
+	writeRepeated(h, b, len(password))
+	for n := len(password); n > 0; n >>= 1 {
+		if n&1 != 0 {
+			h.Write(b)
+		} else {
+			h.Write(password)
+		}
+	}
+	a := h.Sum(nil)
+
+	h = newHash()
+	for range password {
+		h.Write(password)
+	}
+	p := repeatTo(h.Sum(nil), len(password))
+
+	h = newHash()
+	for i := 0; i < 16+int(a[0]); i++ {
+		h.Write(salt)
+	}
+	s := repeatTo(h.Sum(nil), len(salt))
+
+	c := a
+	for i := 0; i < rounds; i++ {
+		h = newHash()
+		if i&1 != 0 {
+			h.Write(p)
+		} else {
+			h.Write(c)
+		}
+		if i%3 != 0 {
+			h.Write(s)
+		}
+		if i%7 != 0 {
+			h.Write(p)
+		}
+		if i&1 != 0 {
+			h.Write(c)
+		} else {
+			h.Write(p)
+		}
+		c = h.Sum(nil)
+	}
+
+	var out strings.Builder
+	out.WriteString("$" + id + "$")
+	if customRounds {
+		out.WriteString("rounds=" + strconv.Itoa(rounds) + "$")
+	}
+	out.Write(salt)
+	out.WriteByte('$')
+	if id == "5" {
+		for _, g := range sha256CryptOrder {
+			encode24(&out, c[g[0]], c[g[1]], c[g[2]], 4)
+		}
+		encode24(&out, 0, c[31], c[30], 3)
+	} else {
+		for _, g := range sha512CryptOrder {
+			encode24(&out, c[g[0]], c[g[1]], c[g[2]], 4)
+		}
+		encode24(&out, 0, 0, c[63], 2)
+	}
+	return out.String()
+}
+
+func sum(newHash func() hash.Hash, parts ...[]byte) []byte {
+	h := newHash()
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return h.Sum(nil)
+}
+
+// writeRepeated writes n bytes of block, repeated as often as needed.
+func writeRepeated(h hash.Hash, block []byte, n int) {
+	for ; n > len(block); n -= len(block) {
+		h.Write(block)
+	}
+	h.Write(block[:n])
+}
+
+// repeatTo returns block repeated and truncated to n bytes.
+func repeatTo(block []byte, n int) []byte {
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		out = append(out, block[:min(len(block), n-len(out))]...)
+	}
+	return out
+}
+
+// encode24 writes n crypt-base64 characters of the 24-bit value b2 b1 b0,
+// least significant six bits first.
+func encode24(out *strings.Builder, b2, b1, b0 byte, n int) {
+	w := uint(b2)<<16 | uint(b1)<<8 | uint(b0)
+	for ; n > 0; n-- {
+		out.WriteByte(cryptAlphabet[w&0x3f])
+		w >>= 6
+	}
+}
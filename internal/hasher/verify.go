@@ -0,0 +1,289 @@
+package hasher
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrUnsupportedHash is returned by Verify for hash formats it cannot read.
+var ErrUnsupportedHash = errors.New("unsupported hash format")
+
+// Limits on the cost parameters Verify accepts, so that a hostile stored
+// hash cannot exhaust memory or CPU. They are well above what any real
+// deployment uses.
+const (
+	maxArgon2Memory = 4 << 20 // KiB, 4 GiB
+	maxArgon2Time   = 1 << 10
+	maxScryptMemory = 4 << 30 // bytes, 128 * r * N
+	maxScryptP      = 1 << 6
+	maxIterations   = 1 << 24 // PBKDF2, SCRAM and SHA-crypt rounds
+)
+
+// Identify names the format of an encoded hash as one of the values Verify
+// understands, or returns ErrUnsupportedHash.
+func Identify(encoded string) (string, error) {
+	switch {
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		return "bcrypt", nil
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return "argon2id", nil
+	case strings.HasPrefix(encoded, "$argon2i$"):
+		return "argon2i", nil
+	case strings.HasPrefix(encoded, "$scrypt$"):
+		return "scrypt", nil
+	case strings.HasPrefix(encoded, "$6$"):
+		return "sha512crypt", nil
+	case strings.HasPrefix(encoded, "$5$"):
+		return "sha256crypt", nil
+	case strings.HasPrefix(encoded, "$1$"):
+		return "md5crypt", nil
+	case strings.HasPrefix(encoded, "$apr1$"):
+		return "apr1", nil
+	case strings.HasPrefix(encoded, "$pbkdf2$"):
+		return "pbkdf2-sha1", nil
+	case strings.HasPrefix(encoded, "$pbkdf2-sha256$"), strings.HasPrefix(encoded, "pbkdf2_sha256$"):
+		return "pbkdf2-sha256", nil
+	case strings.HasPrefix(encoded, "$pbkdf2-sha512$"):
+		return "pbkdf2-sha512", nil
+	case strings.HasPrefix(encoded, "SCRAM-SHA-256$"):
+		return "scram-sha-256", nil
+	}
+	return "", ErrUnsupportedHash
+}
+
+// Verify reports whether password matches the encoded hash. It reads every
+// format Hash writes, plus the related formats found on legacy systems:
+//
+//	bcrypt          $2a$, $2b$, $2y$
+//	argon2          $argon2id$, $argon2i$ (PHC strings)
+//	scrypt          $scrypt$ln=...,r=...,p=...$ (passlib)
+//	crypt(3)        $6$, $5$ (SHA-crypt), $1$ (MD5-crypt), $apr1$
+//	pbkdf2          $pbkdf2$, $pbkdf2-sha256$, $pbkdf2-sha512$ (passlib),
+//	                pbkdf2_sha256$ (Django)
+//	scram-sha-256   SCRAM-SHA-256$<iter>:<salt>$<StoredKey>:<ServerKey>
+//
+// A malformed hash of a known format is reported as an error.
+func Verify(encoded, password string) (bool, error) {
+	alg, err := Identify(encoded)
+	if err != nil {
+		return false, err
+	}
+	pw := []byte(password)
+	switch alg {
+	case "bcrypt":
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), pw)
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	case "argon2id", "argon2i":
+		return verifyArgon2(alg, encoded, pw)
+	case "scrypt":
+		return verifyScrypt(encoded, pw)
+	case "sha512crypt", "sha256crypt":
+		id, salt, rounds, err := parseShaCrypt(encoded)
+		if err != nil {
+			return false, err
+		}
+		return equal(shaCrypt(id, pw, salt, rounds), encoded), nil
+	case "md5crypt", "apr1":
+		magic := "$1$"
+		if alg == "apr1" {
+			magic = "$apr1$"
+		}
+		rest := strings.TrimPrefix(encoded, magic)
+		sep := strings.IndexByte(rest, '$')
+		if sep < 0 {
+			return false, errors.New("malformed MD5-crypt hash")
+		}
+		return equal(md5Crypt(magic, pw, []byte(rest[:sep])), encoded), nil
+	case "scram-sha-256":
+		return verifySCRAM(encoded, pw)
+	}
+	return verifyPBKDF2(alg, encoded, pw)
+}
+
+// verifyArgon2 checks $argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>.
+func verifyArgon2(alg, encoded string, pw []byte) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return false, fmt.Errorf("malformed %s hash", alg)
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, fmt.Errorf("malformed %s version %q", alg, parts[2])
+	}
+	if version != argon2.Version {
+		return false, fmt.Errorf("unsupported %s version %d", alg, version)
+	}
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, fmt.Errorf("malformed %s parameters %q", alg, parts[3])
+	}
+	switch {
+	case time < 1 || time > maxArgon2Time:
+		return false, fmt.Errorf("invalid %s time cost t=%d", alg, time)
+	case threads < 1:
+		return false, fmt.Errorf("invalid %s parallelism p=%d", alg, threads)
+	case memory < 8*uint32(threads) || memory > maxArgon2Memory:
+		return false, fmt.Errorf("invalid %s memory cost m=%d", alg, memory)
+	}
+	salt, err := b64.DecodeString(parts[4])
+	if err != nil {
+		return false, fmt.Errorf("malformed %s salt: %w", alg, err)
+	}
+	want, err := b64.DecodeString(parts[5])
+	if err != nil || len(want) == 0 {
+		return false, fmt.Errorf("malformed %s hash value", alg)
+	}
+	var got []byte
+	if alg == "argon2id" {
+		got = argon2.IDKey(pw, salt, time, memory, threads, uint32(len(want)))
+	} else {
+		got = argon2.Key(pw, salt, time, memory, threads, uint32(len(want)))
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
+
+// verifyScrypt checks $scrypt$ln=15,r=8,p=1$<salt>$<hash>.
+func verifyScrypt(encoded string, pw []byte) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 {
+		return false, errors.New("malformed scrypt hash")
+	}
+	var logN, r, p int
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &logN, &r, &p); err != nil || logN < 1 || logN > 30 || r < 1 || p < 1 {
+		return false, fmt.Errorf("malformed scrypt parameters %q", parts[2])
+	}
+	// scrypt needs 128 * r * N bytes.
+	if r > maxScryptMemory>>(7+logN) || p > maxScryptP {
+		return false, fmt.Errorf("scrypt parameters %q exceed the supported cost", parts[2])
+	}
+	salt, err := b64.DecodeString(parts[3])
+	if err != nil {
+		return false, fmt.Errorf("malformed scrypt salt: %w", err)
+	}
+	want, err := b64.DecodeString(parts[4])
+	if err != nil || len(want) == 0 {
+		return false, errors.New("malformed scrypt hash value")
+	}
+	got, err := scrypt.Key(pw, salt, 1<<logN, r, p, len(want))
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
+
+// verifyPBKDF2 checks passlib's $pbkdf2[-sha256|-sha512]$<iter>$<salt>$<hash>
+// (adapted base64) and Django's pbkdf2_sha256$<iter>$<salt>$<base64 hash>,
+// whose salt is used as plain text.
+func verifyPBKDF2(alg, encoded string, pw []byte) (bool, error) {
+	newHash := map[string]func() hash.Hash{
+		"pbkdf2-sha1":   sha1.New,
+		"pbkdf2-sha256": sha256.New,
+		"pbkdf2-sha512": sha512.New,
+	}[alg]
+	parts := strings.Split(encoded, "$")
+	var iterField string
+	var salt, want []byte
+	var err error
+	if strings.HasPrefix(encoded, "pbkdf2_sha256$") {
+		if len(parts) != 4 {
+			return false, errors.New("malformed Django PBKDF2 hash")
+		}
+		iterField, salt = parts[1], []byte(parts[2])
+		want, err = base64.StdEncoding.DecodeString(parts[3])
+	} else {
+		if len(parts) != 5 {
+			return false, fmt.Errorf("malformed %s hash", alg)
+		}
+		iterField = parts[2]
+		if salt, err = ab64Decode(parts[3]); err != nil {
+			return false, fmt.Errorf("malformed %s salt: %w", alg, err)
+		}
+		want, err = ab64Decode(parts[4])
+	}
+	if err != nil || len(want) == 0 {
+		return false, fmt.Errorf("malformed %s hash value", alg)
+	}
+	iterations, err := strconv.Atoi(iterField)
+	if err != nil || iterations <= 0 || iterations > maxIterations {
+		return false, fmt.Errorf("invalid %s iteration count %q", alg, iterField)
+	}
+	got := pbkdf2.Key(pw, salt, iterations, len(want), newHash)
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
+
+// verifySCRAM recomputes the StoredKey and ServerKey of a PostgreSQL
+// SCRAM-SHA-256 verifier.
+func verifySCRAM(encoded string, pw []byte) (bool, error) {
+	head, keys, ok := strings.Cut(strings.TrimPrefix(encoded, "SCRAM-SHA-256$"), "$")
+	if !ok {
+		return false, errors.New("malformed SCRAM verifier")
+	}
+	iterField, saltField, ok1 := strings.Cut(head, ":")
+	storedField, serverField, ok2 := strings.Cut(keys, ":")
+	if !ok1 || !ok2 {
+		return false, errors.New("malformed SCRAM verifier")
+	}
+	iterations, err := strconv.Atoi(iterField)
+	if err != nil || iterations <= 0 || iterations > maxIterations {
+		return false, fmt.Errorf("invalid SCRAM iteration count %q", iterField)
+	}
+	salt, err1 := base64.StdEncoding.DecodeString(saltField)
+	stored, err2 := base64.StdEncoding.DecodeString(storedField)
+	server, err3 := base64.StdEncoding.DecodeString(serverField)
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return false, fmt.Errorf("malformed SCRAM verifier: %w", err)
+	}
+	gotStored, gotServer := scramKeys(pw, salt, iterations)
+	return subtle.ConstantTimeCompare(gotStored, stored)&subtle.ConstantTimeCompare(gotServer, server) == 1, nil
+}
+
+// parseShaCrypt splits a $5$/$6$ hash into its id, salt and rounds.
+func parseShaCrypt(encoded string) (id string, salt []byte, rounds int, err error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) < 4 || parts[0] != "" || (parts[1] != "5" && parts[1] != "6") {
+		return "", nil, 0, errors.New("not a SHA-crypt hash")
+	}
+	id, rest := parts[1], parts[2:]
+	if strings.HasPrefix(rest[0], "rounds=") {
+		rounds, err = strconv.Atoi(strings.TrimPrefix(rest[0], "rounds="))
+		if err != nil || rounds <= 0 {
+			return "", nil, 0, fmt.Errorf("invalid rounds in %q", rest[0])
+		}
+		// crypt(3) accepts up to 999,999,999 rounds, minutes of CPU time.
+		if rounds > maxIterations {
+			return "", nil, 0, fmt.Errorf("SHA-crypt %s exceeds the supported cost", rest[0])
+		}
+		rest = rest[1:]
+	}
+	if len(rest) != 2 {
+		return "", nil, 0, errors.New("malformed SHA-crypt hash")
+	}
+	return id, []byte(rest[0]), rounds, nil
+}
+
+// ab64Decode reverses ab64, tolerating padding.
+func ab64Decode(s string) ([]byte, error) {
+	return b64.DecodeString(strings.TrimRight(strings.ReplaceAll(s, ".", "+"), "="))
+}
+
+// equal compares two strings in constant time.
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
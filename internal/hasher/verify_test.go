@@ -0,0 +1,83 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyRoundTrip(t *testing.T) {
+	for _, alg := range Algorithms {
+		encoded, err := Hash(alg, "correct horse")
+		if err != nil {
+			t.Fatalf("Hash(%s): %v", alg, err)
+		}
+		if ok, err := Verify(encoded, "correct horse"); !ok || err != nil {
+			t.Errorf("Verify(%s hash, right password) = %v, %v", alg, ok, err)
+		}
+		if ok, err := Verify(encoded, "wrong horse"); ok || err != nil {
+			t.Errorf("Verify(%s hash, wrong password) = %v, %v", alg, ok, err)
+		}
+	}
+}
+
+// TestVerifyCostLimits checks that hashes asking for an implausible cost
+// are rejected before any work is done.
+func TestVerifyCostLimits(t *testing.T) {
+	tests := []struct {
+		name, encoded, want string
+	}{
+		{"sha512crypt rounds", "$6$rounds=999999999$saltsalt$" + strings.Repeat("a", 86), "exceeds the supported cost"},
+		{"sha256crypt rounds", "$5$rounds=16777217$saltsalt$" + strings.Repeat("a", 43), "exceeds the supported cost"},
+		{"sha512crypt zero rounds", "$6$rounds=0$saltsalt$" + strings.Repeat("a", 86), "invalid rounds"},
+		{"argon2 memory", "$argon2id$v=19$m=4294967295,t=1,p=1$c2FsdHNhbHQ$aGFzaGhhc2g", "invalid argon2id memory cost"},
+		{"argon2 time", "$argon2id$v=19$m=65536,t=100000,p=1$c2FsdHNhbHQ$aGFzaGhhc2g", "invalid argon2id time cost"},
+		{"argon2 parallelism", "$argon2id$v=19$m=65536,t=3,p=0$c2FsdHNhbHQ$aGFzaGhhc2g", "invalid argon2id parallelism"},
+		{"scrypt memory", "$scrypt$ln=30,r=8,p=1$c2FsdA$aGFzaA", "exceed the supported cost"},
+		{"scrypt parallelism", "$scrypt$ln=10,r=8,p=1000$c2FsdA$aGFzaA", "exceed the supported cost"},
+		{"pbkdf2 iterations", "$pbkdf2-sha256$2000000000$c2FsdA$aGFzaA", "invalid pbkdf2-sha256 iteration count"},
+		{"django iterations", "pbkdf2_sha256$2000000000$salt$aGFzaA==", "invalid pbkdf2-sha256 iteration count"},
+		{"scram iterations", "SCRAM-SHA-256$2000000000:c2FsdA==$aGFzaA==:aGFzaA==", "invalid SCRAM iteration count"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			ok, err := Verify(tt.encoded, "secret")
+			if ok || err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Verify = %v, %v, want an error containing %q", ok, err, tt.want)
+			}
+			if d := time.Since(start); d > time.Second {
+				t.Errorf("rejecting the hash took %v", d)
+			}
+		})
+	}
+}
+
+func TestVerifyShaCryptRounds(t *testing.T) {
+	// Explicit rounds within the limit are honoured.
+	encoded := shaCrypt("5", []byte("secret"), []byte("saltsalt"), 10000)
+	if !strings.HasPrefix(encoded, "$5$rounds=10000$") {
+		t.Fatalf("shaCrypt wrote %q", encoded)
+	}
+	if ok, err := Verify(encoded, "secret"); !ok || err != nil {
+		t.Errorf("Verify(%q) = %v, %v", encoded, ok, err)
+	}
+}
+
+// FuzzVerify checks that no stored hash, however malformed, panics.
+func FuzzVerify(f *testing.F) {
+	for _, alg := range Algorithms {
+		encoded, err := Hash(alg, "secret")
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(encoded)
+	}
+	f.Add("$argon2i$v=19$m=8,t=1,p=1$$AA")
+	f.Add("$scrypt$ln=1,r=1,p=1$$AA")
+	f.Add("$6$rounds=1000$$")
+	f.Add("pbkdf2_sha256$1$$AA==")
+	f.Fuzz(func(t *testing.T, encoded string) {
+		_, _ = Verify(encoded, "secret")
+	})
+}
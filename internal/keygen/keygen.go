@@ -0,0 +1,158 @@
+package keygen
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Types are the key types supported by Generate.
+var Types = []string{"ed25519", "ecdsa-p256", "ecdsa-p384", "rsa", "wireguard", "jwt-hs256", "jwt-hs512"}
+
+const (
+	DefaultRSABits = 3072
+	MinRSABits     = 2048
+)
+
+type Options struct {
+	Type       string
+	Format     string // pem or openssh; ignored for wireguard and jwt keys
+	Bits       int    // RSA modulus size
+	Comment    string // OpenSSH public key comment
+	Passphrase []byte // encrypts the private key when non-empty
+}
+
+// KeyPair holds encoded key material. Public is empty for symmetric keys.
+type KeyPair struct {
+	Private []byte
+	Public  []byte
+}
+
+// Generate creates a new key of the requested type and encodes it.
+func Generate(opts Options) (*KeyPair, error) {
+	switch opts.Type {
+	case "wireguard":
+		if len(opts.Passphrase) > 0 {
+			return nil, errors.New("wireguard keys cannot be passphrase protected")
+		}
+		return wireGuard()
+	case "jwt-hs256", "jwt-hs512":
+		if len(opts.Passphrase) > 0 {
+			return nil, errors.New("JWT signing keys cannot be passphrase protected")
+		}
+		size := 32
+		if opts.Type == "jwt-hs512" {
+			size = 64
+		}
+		secret := make([]byte, size)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		return &KeyPair{Private: []byte(base64.RawURLEncoding.EncodeToString(secret) + "\n")}, nil
+	}
+
+	key, err := newSigner(opts)
+	if err != nil {
+		return nil, err
+	}
+	switch opts.Format {
+	case "", "pem":
+		return encodePEM(key, opts.Passphrase)
+	case "openssh":
+		return encodeOpenSSH(key, opts.Comment, opts.Passphrase)
+	}
+	return nil, fmt.Errorf("unknown key format %q (want pem or openssh)", opts.Format)
+}
+
+func newSigner(opts Options) (crypto.Signer, error) {
+	switch opts.Type {
+	case "ed25519":
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	case "ecdsa-p256":
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ecdsa-p384":
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "rsa":
+		bits := opts.Bits
+		if bits == 0 {
+			bits = DefaultRSABits
+		}
+		if bits < MinRSABits {
+			return nil, fmt.Errorf("RSA keys must be at least %d bits", MinRSABits)
+		}
+		return rsa.GenerateKey(rand.Reader, bits)
+	}
+	return nil, fmt.Errorf("unknown key type %q (want one of %s)", opts.Type, strings.Join(Types, ", "))
+}
+
+// encodePEM writes the private key as PKCS#8 (encrypted with PBES2 when a
+// passphrase is given) and the public key as PKIX.
+func encodePEM(key crypto.Signer, passphrase []byte) (*KeyPair, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	block := &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	if len(passphrase) > 0 {
+		if block, err = encryptPKCS8(der, passphrase); err != nil {
+			return nil, err
+		}
+	}
+	pub, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &KeyPair{
+		Private: pem.EncodeToMemory(block),
+		Public:  pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}),
+	}, nil
+}
+
+// encodeOpenSSH writes the private key in OpenSSH format and the public key
+// as an authorized_keys line.
+func encodeOpenSSH(key crypto.Signer, comment string, passphrase []byte) (*KeyPair, error) {
+	var block *pem.Block
+	var err error
+	if len(passphrase) > 0 {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, comment, passphrase)
+	} else {
+		block, err = ssh.MarshalPrivateKey(key, comment)
+	}
+	if err != nil {
+		return nil, err
+	}
+	pub, err := ssh.NewPublicKey(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	line := strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(pub)), "\n")
+	if comment != "" {
+		line += " " + comment
+	}
+	return &KeyPair{Private: pem.EncodeToMemory(block), Public: []byte(line + "\n")}, nil
+}
+
+// wireGuard returns a Curve25519 key pair in WireGuard's base64 format.
+func wireGuard() (*KeyPair, error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyPair{
+		Private: []byte(base64.StdEncoding.EncodeToString(key.Bytes()) + "\n"),
+		Public:  []byte(base64.StdEncoding.EncodeToString(key.PublicKey().Bytes()) + "\n"),
+	}, nil
+}
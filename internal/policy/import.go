@@ -0,0 +1,183 @@
+package policy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// ParseFile reads an Active Directory policy export: a secedit INF file
+// ("secedit /export /cfg"), recognised by its [System Access] section, or
+// an LDIF export of msDS-PasswordSettings objects or the domain object.
+func ParseFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	text := decodeText(data)
+	if strings.Contains(strings.ToLower(text), "[system access]") {
+		return ParseSecedit(text)
+	}
+	return ParseLDIF(text)
+}
+
+// ParseSecedit reads MinimumPasswordLength and PasswordComplexity from the
+// [System Access] section of a secedit export.
+func ParseSecedit(text string) (*Policy, error) {
+	var p Policy
+	found := false
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(line)
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if section != "[system access]" || !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "minimumpasswordlength":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid MinimumPasswordLength %q", value)
+			}
+			p.MinLength, found = n, true
+		case "passwordcomplexity":
+			p.setComplexity(value == "1")
+			found = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.New("no password settings in [System Access]")
+	}
+	return &p, nil
+}
+
+// ParseLDIF reads fine-grained password policies (msDS-PasswordSettings:
+// msDS-MinimumPasswordLength, msDS-PasswordComplexityEnabled) and domain
+// defaults (minPwdLength, pwdProperties). With several entries the result
+// is the strictest combination, so passwords satisfy every one of them.
+func ParseLDIF(text string) (*Policy, error) {
+	var result *Policy
+	for _, entry := range ldifEntries(text) {
+		var p Policy
+		found := false
+		for _, attr := range entry {
+			switch strings.ToLower(attr[0]) {
+			case "msds-minimumpasswordlength", "minpwdlength":
+				n, err := strconv.Atoi(attr[1])
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("invalid %s %q", attr[0], attr[1])
+				}
+				p.MinLength, found = n, true
+			case "msds-passwordcomplexityenabled":
+				p.setComplexity(strings.EqualFold(attr[1], "TRUE"))
+				found = true
+			case "pwdproperties":
+				// Bit 0 is DOMAIN_PASSWORD_COMPLEX.
+				n, err := strconv.Atoi(attr[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid pwdProperties %q", attr[1])
+				}
+				p.setComplexity(n&1 != 0)
+				found = true
+			}
+		}
+		if found {
+			result = Strictest(result, &p)
+		}
+	}
+	if result == nil {
+		return nil, errors.New("no password settings found (expected msDS-PasswordSettings or minPwdLength attributes)")
+	}
+	return result, nil
+}
+
+// setComplexity applies Active Directory's complexity requirements: three
+// of the five character categories and no account name.
+func (p *Policy) setComplexity(on bool) {
+	if on {
+		p.MinClasses = 3
+		p.DisallowUsername = true
+	}
+}
+
+// ldifEntries splits LDIF into entries of attribute/value pairs, unfolding
+// continuation lines and decoding base64 ("::") values.
+func ldifEntries(text string) [][][2]string {
+	var entries [][][2]string
+	var entry [][2]string
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, " ") && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			if len(entry) > 0 {
+				entries = append(entries, entry)
+				entry = nil
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(value, ":") {
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:]))
+			if err != nil {
+				continue
+			}
+			value = string(decoded)
+		}
+		entry = append(entry, [2]string{name, strings.TrimSpace(value)})
+	}
+	if len(entry) > 0 {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// decodeText returns data as a string, converting UTF-16 (which secedit
+// writes, with a byte order mark) to UTF-8.
+func decodeText(data []byte) string {
+	var order binary.ByteOrder
+	switch {
+	case bytes.HasPrefix(data, []byte{0xff, 0xfe}):
+		order = binary.LittleEndian
+	case bytes.HasPrefix(data, []byte{0xfe, 0xff}):
+		order = binary.BigEndian
+	default:
+		return string(bytes.TrimPrefix(data, []byte{0xef, 0xbb, 0xbf}))
+	}
+	data = data[2:]
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
@@ -0,0 +1,115 @@
+package policy
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseFile(t *testing.T) {
+	tests := []struct {
+		file string
+		want Policy
+	}{
+		// secedit /export writes UTF-16LE with a byte order mark.
+		{"testdata/secedit.inf", Policy{MinLength: 12, MinClasses: 3, DisallowUsername: true}},
+		// Two PSOs and the domain defaults: the longest minimum wins, and the
+		// complexity of the admins PSO and the domain applies to all.
+		{"testdata/pso.ldif", Policy{MinLength: 24, MinClasses: 3, DisallowUsername: true}},
+	}
+	for _, tt := range tests {
+		p, err := ParseFile(tt.file)
+		if err != nil {
+			t.Errorf("ParseFile(%s): %v", tt.file, err)
+			continue
+		}
+		if !reflect.DeepEqual(*p, tt.want) {
+			t.Errorf("ParseFile(%s) = %+v, want %+v", tt.file, *p, tt.want)
+		}
+	}
+}
+
+// TestImportedPolicyCheck runs sample passwords through the policy imported
+// from the secedit dump, as pwdforge check --policy-file would.
+func TestImportedPolicyCheck(t *testing.T) {
+	p, err := ParseFile("testdata/secedit.inf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		password, username string
+		failed             []string
+	}{
+		{"Correct-Horse-7", "jdoe", nil},
+		{"correcthorse7!", "jdoe", nil},             // lower, digit, special
+		{"Kōrero宿題2024", "jdoe", nil},               // uncased letters count as a class
+		{"Short1!", "jdoe", []string{"min_length"}}, // 7 characters
+		{"correcthorsebattery", "jdoe", []string{"min_classes"}},
+		{"CORRECTHORSE2024", "jdoe", []string{"min_classes"}},
+		{"Welcome-JDoe-2024", "jdoe", []string{"disallow_username"}},
+		{"Welcome-jd0e-2024", "jdoe", []string{"disallow_username"}},
+		{"Welcome-Al-2024", "al", nil}, // AD skips names under 3 characters
+		{"abc", "", []string{"min_length", "min_classes"}},
+	}
+	for _, tt := range tests {
+		results, err := p.Check(tt.password, Options{Username: tt.username})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Failed(results); !slices.Equal(got, tt.failed) {
+			t.Errorf("Check(%q, %q) failed %v, want %v", tt.password, tt.username, got, tt.failed)
+		}
+		if Passed(results) != (len(tt.failed) == 0) {
+			t.Errorf("Passed(%q) = %v", tt.password, Passed(results))
+		}
+	}
+}
+
+func TestParseSeceditErrors(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"[System Access]\nMinimumPasswordLength = -1\n", `invalid MinimumPasswordLength "-1"`},
+		{"[System Access]\nMinimumPasswordLength = twelve\n", `invalid MinimumPasswordLength "twelve"`},
+		{"[System Access]\nLockoutBadCount = 5\n", "no password settings in [System Access]"},
+		// Settings outside [System Access] are ignored.
+		{"[Event Audit]\nMinimumPasswordLength = 12\n", "no password settings in [System Access]"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseSecedit(tt.text); err == nil || err.Error() != tt.want {
+			t.Errorf("ParseSecedit(%q) error = %v, want %s", tt.text, err, tt.want)
+		}
+	}
+}
+
+func TestParseLDIF(t *testing.T) {
+	// Complexity off in the only entry leaves just the length.
+	p, err := ParseLDIF("dn: CN=Svc\nmsDS-MinimumPasswordLength: 20\nmsDS-PasswordComplexityEnabled: FALSE\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*p, Policy{MinLength: 20}) {
+		t.Errorf("ParseLDIF = %+v", *p)
+	}
+	// pwdProperties bit 0 turns complexity on; other bits do not.
+	for props, want := range map[string]int{"1": 3, "17": 3, "16": 0} {
+		p, err := ParseLDIF("dn: DC=corp\nminPwdLength: 8\npwdProperties: " + props + "\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.MinClasses != want {
+			t.Errorf("pwdProperties %s: MinClasses = %d, want %d", props, p.MinClasses, want)
+		}
+	}
+	for text, want := range map[string]string{
+		"dn: CN=x\nminPwdLength: seven\n": `invalid minPwdLength "seven"`,
+		"dn: CN=x\npwdProperties: on\n":   `invalid pwdProperties "on"`,
+		"dn: CN=x\ncn: nothing useful\n":  "no password settings found",
+		"":                                "no password settings found",
+	} {
+		if _, err := ParseLDIF(text); err == nil || !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("ParseLDIF(%q) error = %v, want %s", text, err, want)
+		}
+	}
+}
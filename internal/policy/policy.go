@@ -0,0 +1,273 @@
+// Package policy checks passwords against an organisation's password
+// policy, typically loaded from the "policy" section of a YAML config file.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"pwdforge/internal/generator"
+	"pwdforge/internal/pwnchecker"
+)
+
+// Policy lists the rules a password must satisfy. Zero values disable a rule.
+type Policy struct {
+	MinLength       int  `yaml:"min_length,omitempty" json:"min_length,omitempty"`
+	MaxLength       int  `yaml:"max_length,omitempty" json:"max_length,omitempty"`
+	RequireUpper    bool `yaml:"require_upper,omitempty" json:"require_upper,omitempty"`
+	RequireLower    bool `yaml:"require_lower,omitempty" json:"require_lower,omitempty"`
+	RequireDigits   bool `yaml:"require_digits,omitempty" json:"require_digits,omitempty"`
+	RequireSpecials bool `yaml:"require_specials,omitempty" json:"require_specials,omitempty"`
+	// RequireLetters asks for any letter, upper or lower case.
+	RequireLetters bool `yaml:"require_letters,omitempty" json:"require_letters,omitempty"`
+	// MinClasses requires characters from at least this many classes: upper,
+	// lower, digits, specials and, as in Active Directory, letters without
+	// case (e.g. CJK). 3 gives the usual "3 of 4" rule.
+	MinClasses int `yaml:"min_classes,omitempty" json:"min_classes,omitempty"`
+	// MaxRepeated is the longest allowed run of one repeated character.
+	MaxRepeated int `yaml:"max_repeated,omitempty" json:"max_repeated,omitempty"`
+	// BannedWords may not appear anywhere in the password, ignoring case and
+	// l33t substitutions ("P@55w0rd" contains "password").
+	BannedWords []string `yaml:"banned_words,omitempty" json:"banned_words,omitempty"`
+	// BannedWordFiles are word lists, one word per line, added to BannedWords.
+	BannedWordFiles  []string `yaml:"banned_word_files,omitempty" json:"banned_word_files,omitempty"`
+	DisallowUsername bool     `yaml:"disallow_username,omitempty" json:"disallow_username,omitempty"`
+	CompanyNames     []string `yaml:"company_names,omitempty" json:"company_names,omitempty"`
+	// MinEntropy is in bits; the generator's exact figure is used when known.
+	MinEntropy float64 `yaml:"min_entropy,omitempty" json:"min_entropy,omitempty"`
+	// NotPwned rejects passwords found in HaveIBeenPwned (needs network).
+	NotPwned bool `yaml:"not_pwned,omitempty" json:"not_pwned,omitempty"`
+
+	// Blocklist holds further banned words loaded by the caller, such as
+	// --banned-words lists and --context values.
+	Blocklist *generator.Blocklist `yaml:"-" json:"-"`
+
+	banned  *generator.Blocklist // BannedWords, BannedWordFiles and Blocklist
+	company *generator.Blocklist
+}
+
+// Result is the outcome of one rule.
+type Result struct {
+	Rule   string `json:"rule"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail"`
+}
+
+// Options carries the context of a check.
+type Options struct {
+	Username string
+	// Entropy overrides the estimated entropy when the generator knows it.
+	Entropy float64
+	// PwnCheck replaces the HaveIBeenPwned lookup; nil uses the public API.
+	PwnCheck func(password string) (bool, int, error)
+}
+
+// Validate reports contradictory or out-of-range settings.
+func (p *Policy) Validate() error {
+	switch {
+	case p.MinLength < 0, p.MaxLength < 0, p.MaxRepeated < 0, p.MinEntropy < 0:
+		return errors.New("policy values must not be negative")
+	case p.MaxLength > 0 && p.MinLength > p.MaxLength:
+		return fmt.Errorf("policy min_length %d exceeds max_length %d", p.MinLength, p.MaxLength)
+	case p.MinClasses < 0 || p.MinClasses > 5:
+		return fmt.Errorf("policy min_classes must be between 0 and 5, got %d", p.MinClasses)
+	}
+	return nil
+}
+
+// Prepare loads the banned word files and builds the matchers used by
+// Check. Check prepares on first use; calling Prepare up front surfaces file
+// errors early and lets copies of p share the loaded lists.
+func (p *Policy) Prepare() error {
+	if p.banned != nil {
+		return nil
+	}
+	banned := p.Blocklist
+	if banned == nil || len(p.BannedWords) > 0 || len(p.BannedWordFiles) > 0 {
+		banned = generator.NewBlocklist(p.BannedWords...)
+		for _, path := range p.BannedWordFiles {
+			if err := banned.LoadFile(path); err != nil {
+				return fmt.Errorf("loading banned words: %w", err)
+			}
+		}
+		banned.Merge(p.Blocklist)
+	}
+	p.banned = banned
+	p.company = generator.NewBlocklist(p.CompanyNames...)
+	return nil
+}
+
+// Check evaluates every enabled rule in a fixed order. The error is only
+// set when a rule could not be evaluated, e.g. the breach lookup failed.
+func (p *Policy) Check(password string, opts Options) ([]Result, error) {
+	if err := p.Prepare(); err != nil {
+		return nil, err
+	}
+	var results []Result
+	add := func(rule string, passed bool, detail string, args ...any) {
+		results = append(results, Result{Rule: rule, Passed: passed, Detail: fmt.Sprintf(detail, args...)})
+	}
+	length := utf8.RuneCountInString(password)
+	if p.MinLength > 0 {
+		add("min_length", length >= p.MinLength, "%d characters, need at least %d", length, p.MinLength)
+	}
+	if p.MaxLength > 0 {
+		add("max_length", length <= p.MaxLength, "%d characters, allowed at most %d", length, p.MaxLength)
+	}
+
+	upper, lower, digits, specials, uncased := classes(password)
+	for _, c := range []struct {
+		rule     string
+		required bool
+		present  bool
+		article  string
+		name     string
+	}{
+		{"require_upper", p.RequireUpper, upper, "an", "uppercase letter"},
+		{"require_lower", p.RequireLower, lower, "a", "lowercase letter"},
+		{"require_digits", p.RequireDigits, digits, "a", "digit"},
+		{"require_specials", p.RequireSpecials, specials, "a", "special character"},
+	} {
+		if !c.required {
+			continue
+		}
+		if c.present {
+			add(c.rule, true, "contains %s %s", c.article, c.name)
+		} else {
+			add(c.rule, false, "no %s", c.name)
+		}
+	}
+	if p.RequireLetters {
+		if upper || lower || uncased {
+			add("require_letters", true, "contains a letter")
+		} else {
+			add("require_letters", false, "no letter")
+		}
+	}
+	if p.MinClasses > 0 {
+		n := 0
+		for _, present := range []bool{upper, lower, digits, specials, uncased} {
+			if present {
+				n++
+			}
+		}
+		add("min_classes", n >= p.MinClasses, "%d character classes, need at least %d", n, p.MinClasses)
+	}
+	if p.MaxRepeated > 0 {
+		run := longestRun(password)
+		add("max_repeated", run <= p.MaxRepeated, "longest run of one character is %d, allowed %d", run, p.MaxRepeated)
+	}
+	if p.banned.Len() > 0 {
+		if w, found := p.banned.Match(password); found {
+			add("banned_words", false, "contains banned word %q", w)
+		} else {
+			add("banned_words", true, "none of %d banned words", p.banned.Len())
+		}
+	}
+	if p.DisallowUsername {
+		switch {
+		case opts.Username == "":
+			add("disallow_username", true, "no username given")
+		case utf8.RuneCountInString(opts.Username) < 3:
+			// Active Directory skips names this short as well.
+			add("disallow_username", true, "usernames shorter than 3 characters are not checked")
+		case matches(generator.NewBlocklist(opts.Username), password):
+			add("disallow_username", false, "contains the username")
+		default:
+			add("disallow_username", true, "does not contain the username")
+		}
+	}
+	if p.company.Len() > 0 {
+		if w, found := p.company.Match(password); found {
+			add("company_names", false, "contains company name %q", w)
+		} else {
+			add("company_names", true, "no company names")
+		}
+	}
+	if p.MinEntropy > 0 {
+		entropy := opts.Entropy
+		if entropy == 0 {
+			_, entropy, _ = generator.CheckPasswordStrength(password)
+		}
+		add("min_entropy", entropy >= p.MinEntropy, "%.2f bits, need at least %.2f", entropy, p.MinEntropy)
+	}
+	if p.NotPwned {
+		check := opts.PwnCheck
+		if check == nil {
+			check = pwnchecker.CheckPasswordPwned
+		}
+		pwned, count, err := check(password)
+		if err != nil {
+			return results, fmt.Errorf("breach check failed: %w", err)
+		}
+		if pwned {
+			add("not_pwned", false, "found in %d breaches", count)
+		} else {
+			add("not_pwned", true, "not found in known breaches")
+		}
+	}
+	return results, nil
+}
+
+// Passed reports whether every result passed.
+func Passed(results []Result) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the names of the rules that did not pass.
+func Failed(results []Result) []string {
+	var rules []string
+	for _, r := range results {
+		if !r.Passed {
+			rules = append(rules, r.Rule)
+		}
+	}
+	return rules
+}
+
+// classes reports which character classes occur in s. Any character that
+// is not a letter or digit counts as special.
+func classes(s string) (upper, lower, digits, specials, uncased bool) {
+	for _, r := range s {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digits = true
+		case unicode.IsLetter(r):
+			uncased = true
+		default:
+			specials = true
+		}
+	}
+	return
+}
+
+func longestRun(s string) int {
+	longest, run := 0, 0
+	var prev rune
+	for i, r := range []rune(s) {
+		if i > 0 && r == prev {
+			run++
+		} else {
+			run = 1
+		}
+		prev = r
+		longest = max(longest, run)
+	}
+	return longest
+}
+
+func matches(b *generator.Blocklist, password string) bool {
+	_, found := b.Match(password)
+	return found
+}
@@ -0,0 +1,125 @@
+package policy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"pwdforge/internal/generator"
+)
+
+func TestCheck(t *testing.T) {
+	words := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(words, []byte("# site words\nsummer\nwinter\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p := &Policy{
+		MinLength:        10,
+		MaxLength:        20,
+		RequireUpper:     true,
+		RequireLower:     true,
+		RequireDigits:    true,
+		RequireSpecials:  true,
+		MaxRepeated:      2,
+		BannedWords:      []string{"password"},
+		BannedWordFiles:  []string{words},
+		DisallowUsername: true,
+		CompanyNames:     []string{"Acme"},
+		Blocklist:        generator.NewBlocklist("hunter"),
+	}
+	tests := []struct {
+		password string
+		failed   []string
+	}{
+		{"Tr0ub4dor&x", nil},
+		{"Tr0b&x", []string{"min_length"}},
+		{"Tr0ub4dor&xTr0ub4dor&x", []string{"max_length"}},
+		{"tr0ub4dor&x", []string{"require_upper"}},
+		{"TR0UB4DOR&X", []string{"require_lower"}},
+		{"Troubador&x", []string{"require_digits"}},
+		{"Tr0ub4dor9x", []string{"require_specials"}},
+		{"Tr0ub4dor&xxx", []string{"max_repeated"}},
+		{"P@ssw0rd-2024", []string{"banned_words"}},
+		{"Summer-2024!", []string{"banned_words"}},  // from the word file
+		{"Hunt3r-2024!x", []string{"banned_words"}}, // from Blocklist
+		{"xJd0e-2024!x", []string{"disallow_username"}},
+		{"@cme-2024!Xy", []string{"company_names"}},
+		{"aaa", []string{"min_length", "require_upper", "require_digits", "require_specials", "max_repeated"}},
+	}
+	for _, tt := range tests {
+		results, err := p.Check(tt.password, Options{Username: "jdoe"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Failed(results); !slices.Equal(got, tt.failed) {
+			t.Errorf("Check(%q) failed %v, want %v", tt.password, got, tt.failed)
+		}
+	}
+}
+
+func TestCheckResults(t *testing.T) {
+	p := &Policy{MinLength: 8, MinClasses: 3, RequireLetters: true, MinEntropy: 60}
+	results, err := p.Check("abc12345", Options{Entropy: 41.36})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Result{
+		{"min_length", true, "8 characters, need at least 8"},
+		{"require_letters", true, "contains a letter"},
+		{"min_classes", false, "2 character classes, need at least 3"},
+		{"min_entropy", false, "41.36 bits, need at least 60.00"},
+	}
+	if !slices.Equal(results, want) {
+		t.Errorf("Check results:\n got %v\nwant %v", results, want)
+	}
+}
+
+func TestCheckNotPwned(t *testing.T) {
+	p := &Policy{NotPwned: true}
+	breached := func(password string) (bool, int, error) {
+		return password == "letmein", 42, nil
+	}
+	for password, want := range map[string]Result{
+		"letmein":       {"not_pwned", false, "found in 42 breaches"},
+		"Tr0ub4dor&x-9": {"not_pwned", true, "not found in known breaches"},
+	} {
+		results, err := p.Check(password, Options{PwnCheck: breached})
+		if err != nil || len(results) != 1 || results[0] != want {
+			t.Errorf("Check(%q) = %v, %v, want %v", password, results, err, want)
+		}
+	}
+	offline := errors.New("offline")
+	_, err := p.Check("x", Options{PwnCheck: func(string) (bool, int, error) { return false, 0, offline }})
+	if !errors.Is(err, offline) {
+		t.Errorf("Check with a failing lookup = %v, want it wrapped", err)
+	}
+}
+
+func TestCheckBannedWordFileMissing(t *testing.T) {
+	p := &Policy{BannedWordFiles: []string{filepath.Join(t.TempDir(), "missing.txt")}}
+	if _, err := p.Check("anything", Options{}); err == nil || !strings.HasPrefix(err.Error(), "loading banned words: ") {
+		t.Errorf("Check = %v, want a load error", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		policy Policy
+		want   string // empty for a valid policy
+	}{
+		{Policy{MinLength: 12, MaxLength: 64, MinClasses: 3}, ""},
+		{Policy{MinLength: -1}, "policy values must not be negative"},
+		{Policy{MinEntropy: -0.5}, "policy values must not be negative"},
+		{Policy{MinLength: 20, MaxLength: 16}, "policy min_length 20 exceeds max_length 16"},
+		{Policy{MinClasses: 6}, "policy min_classes must be between 0 and 5, got 6"},
+	}
+	for _, tt := range tests {
+		err := tt.policy.Validate()
+		if (err == nil) != (tt.want == "") || (err != nil && err.Error() != tt.want) {
+			t.Errorf("Validate(%+v) = %v, want %q", tt.policy, err, tt.want)
+		}
+	}
+}
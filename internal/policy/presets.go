@@ -0,0 +1,120 @@
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"pwdforge/internal/generator"
+)
+
+// commonPasswords seeds the blocklists that standards require. It is kept
+// short on purpose: the words are matched as substrings, and l33t spellings
+// such as "passw0rd" match without being listed.
+var commonPasswords = []string{
+	"password", "qwerty", "letmein", "welcome", "iloveyou",
+	"admin", "monkey", "dragon", "football", "baseball", "abc123", "123456",
+}
+
+// Presets are built-in policies for common standards. They describe the
+// rules as published; pair them with a policy file for site-specific words.
+var Presets = map[string]Policy{
+	// NIST SP 800-63B rev. 4: at least 15 characters for single-factor
+	// passwords, no composition rules, and a blocklist of common and
+	// context-specific words. Verifiers must accept at least 64 characters,
+	// which is a floor for the maximum, so the preset sets no maximum.
+	"nist-800-63b": {
+		MinLength:        15,
+		BannedWords:      commonPasswords,
+		DisallowUsername: true,
+	},
+	// PCI DSS v4.0 requirement 8.3.6: at least 12 characters with both
+	// numeric and alphabetic characters.
+	"pci-dss-4": {
+		MinLength:      12,
+		RequireLetters: true,
+		RequireDigits:  true,
+	},
+	// Active Directory "Password must meet complexity requirements" with the
+	// Default Domain Policy minimum length of 7.
+	"ad-complexity": {
+		MinLength:        7,
+		MinClasses:       3,
+		DisallowUsername: true,
+	},
+	// CIS Microsoft Windows benchmarks 1.1.5 and 1.1.6: at least 14
+	// characters and AD complexity enabled.
+	"cis-windows": {
+		MinLength:        14,
+		MinClasses:       3,
+		DisallowUsername: true,
+	},
+}
+
+// PresetNames returns the preset names in sorted order.
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupPreset returns a copy of the named preset.
+func LookupPreset(name string) (*Policy, error) {
+	p, ok := Presets[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown policy preset %q (want one of %s)", name, strings.Join(PresetNames(), ", "))
+	}
+	p.BannedWords = slices.Clone(p.BannedWords)
+	p.CompanyNames = slices.Clone(p.CompanyNames)
+	return &p, nil
+}
+
+// Strictest combines two policies into one that a password satisfies only
+// if it satisfies both. A nil argument returns the other.
+func Strictest(a, b *Policy) *Policy {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	// smallest returns the lower limit, treating 0 as "no limit".
+	smallest := func(x, y int) int {
+		if x == 0 || (y != 0 && y < x) {
+			return y
+		}
+		return x
+	}
+	return &Policy{
+		MinLength:        max(a.MinLength, b.MinLength),
+		MaxLength:        smallest(a.MaxLength, b.MaxLength),
+		RequireUpper:     a.RequireUpper || b.RequireUpper,
+		RequireLower:     a.RequireLower || b.RequireLower,
+		RequireDigits:    a.RequireDigits || b.RequireDigits,
+		RequireSpecials:  a.RequireSpecials || b.RequireSpecials,
+		RequireLetters:   a.RequireLetters || b.RequireLetters,
+		MinClasses:       max(a.MinClasses, b.MinClasses),
+		MaxRepeated:      smallest(a.MaxRepeated, b.MaxRepeated),
+		BannedWords:      union(a.BannedWords, b.BannedWords),
+		BannedWordFiles:  union(a.BannedWordFiles, b.BannedWordFiles),
+		DisallowUsername: a.DisallowUsername || b.DisallowUsername,
+		CompanyNames:     union(a.CompanyNames, b.CompanyNames),
+		MinEntropy:       max(a.MinEntropy, b.MinEntropy),
+		NotPwned:         a.NotPwned || b.NotPwned,
+		Blocklist:        generator.MergeBlocklists(a.Blocklist, b.Blocklist),
+	}
+}
+
+func union(a, b []string) []string {
+	out := slices.Clone(a)
+	for _, w := range b {
+		if !slices.Contains(out, w) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
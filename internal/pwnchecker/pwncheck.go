@@ -1,6 +1,7 @@
 package pwnchecker
 
 import (
+	"context"
 	"crypto/sha1"
 	"encoding/hex"
 	"fmt"
@@ -10,22 +11,52 @@ import (
 	"strings"
 )
 
+// DefaultAPIURL is the public Pwned Passwords range API.
+const DefaultAPIURL = "https://api.pwnedpasswords.com"
+
+// APIURL is the base URL range queries go to, e.g. a mirror run with
+// "pwdforge serve-range". Requests are made to APIURL + "/range/<prefix>".
+var APIURL = DefaultAPIURL
+
 // CheckPasswordPwned checks if the given password has been pwned using HIBP API.
 func CheckPasswordPwned(password string) (bool, int, error) {
-	hash := sha1.New()
-	_, err := io.WriteString(hash, password)
-	if err != nil {
-		return false, 0, err
+	return CheckHashPwnedAt(context.Background(), APIURL, SHA1Hex(password))
+}
+
+// CheckHashPwned is CheckPasswordPwned for a hex SHA-1 hash of the password,
+// for callers that never see the password itself.
+func CheckHashPwned(sha1Hex string) (bool, int, error) {
+	return CheckHashPwnedAt(context.Background(), APIURL, sha1Hex)
+}
+
+// SHA1Hex returns the hex SHA-1 of password, as range queries use it.
+func SHA1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckHashPwnedAt is CheckHashPwned against the range API at apiURL, with
+// the request bound to ctx.
+func CheckHashPwnedAt(ctx context.Context, apiURL, sha1Hex string) (bool, int, error) {
+	hashStr := strings.ToUpper(sha1Hex)
+	if len(hashStr) != 40 {
+		return false, 0, fmt.Errorf("SHA-1 hash must be 40 hex characters, got %d", len(hashStr))
+	}
+	if _, err := hex.DecodeString(hashStr); err != nil {
+		return false, 0, fmt.Errorf("invalid SHA-1 hash: %w", err)
 	}
-	hashStr := strings.ToUpper(hex.EncodeToString(hash.Sum(nil)))
 
 	prefix := hashStr[:5]
 	suffix := hashStr[5:]
 
-	// Remove the space after /range/ in the URL
-	url := "https://api.pwnedpasswords.com/range/" + prefix
-
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(apiURL, "/")+"/range/"+prefix, nil)
+	if err != nil {
+		return false, 0, err
+	}
+	// Padding hides from network observers how many suffixes came back.
+	req.Header.Set("Add-Padding", "true")
+	req.Header.Set("User-Agent", "pwdforge")
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return false, 0, err
 	}
@@ -42,11 +73,13 @@ func CheckPasswordPwned(password string) (bool, int, error) {
 
 	lines := strings.Split(string(body), "\n")
 	for _, line := range lines {
-		if strings.Contains(line, suffix) {
-			countStr := strings.TrimSpace(strings.Split(line, ":")[1])
-			count, _ := strconv.Atoi(countStr)
-			return true, count, nil
+		lineSuffix, countStr, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || !strings.EqualFold(lineSuffix, suffix) {
+			continue
 		}
+		count, _ := strconv.Atoi(countStr)
+		// Padding entries have a count of zero.
+		return count > 0, count, nil
 	}
 
 	return false, 0, nil
@@ -0,0 +1,291 @@
+// Package pwnrange serves k-anonymity range queries from a local copy of
+// the Pwned Passwords dump, in the format of api.pwnedpasswords.com.
+package pwnrange
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// PrefixLen is the number of hex characters clients send.
+	PrefixLen = 5
+	prefixes  = 1 << (4 * PrefixLen)
+
+	SHA1Len = 40
+	NTLMLen = 32
+
+	// Padded responses hold a random number of entries in this range, like
+	// the public API's Add-Padding option.
+	minPadded = 800
+	maxPadded = 1000
+)
+
+var indexMagic = []byte("PWFRIDX1")
+
+// ErrBadPrefix is returned for prefixes that are not five hex characters.
+var ErrBadPrefix = errors.New("the hash prefix was not in a valid format")
+
+// Dump answers range queries for one hash type.
+type Dump interface {
+	// Range returns the "SUFFIX:COUNT" lines for a validated, upper-case
+	// prefix.
+	Range(prefix string) ([]string, error)
+	// HashLen is SHA1Len or NTLMLen.
+	HashLen() int
+	Close() error
+}
+
+// Open loads a dump: either a single file of "HASH:COUNT" lines sorted by
+// hash, as written by the official downloader, or a directory of
+// per-prefix "<PREFIX>.txt" files holding "SUFFIX:COUNT" lines. Single files
+// are indexed by prefix; the index is cached next to the dump as
+// "<dump>.idx" and rebuilt when the dump changes.
+func Open(path string) (Dump, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return openDir(path)
+	}
+	return openFile(path, info)
+}
+
+// NormalizePrefix validates a client prefix and upper-cases it.
+func NormalizePrefix(prefix string) (string, error) {
+	if len(prefix) != PrefixLen {
+		return "", ErrBadPrefix
+	}
+	if _, err := strconv.ParseUint(prefix, 16, 32); err != nil {
+		return "", ErrBadPrefix
+	}
+	return strings.ToUpper(prefix), nil
+}
+
+// Pad adds zero-count entries with random suffixes until lines has between
+// 800 and 1000 entries, hiding the real size of the range from observers.
+func Pad(lines []string, hashLen int) []string {
+	target := minPadded + rand.IntN(maxPadded-minPadded+1)
+	const hexDigits = "0123456789ABCDEF"
+	suffix := make([]byte, hashLen-PrefixLen)
+	for len(lines) < target {
+		for i := range suffix {
+			suffix[i] = hexDigits[rand.IntN(16)]
+		}
+		lines = append(lines, string(suffix)+":0")
+	}
+	return lines
+}
+
+// fileDump is a sorted single-file dump with a prefix offset index:
+// offsets[p] is the byte offset of the first line whose hash starts with
+// prefix p, and offsets[prefixes] is the file size.
+type fileDump struct {
+	f       *os.File
+	offsets []uint64
+	hashLen int
+}
+
+func openFile(path string, info os.FileInfo) (*fileDump, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	d := &fileDump{f: f}
+	idxPath := path + ".idx"
+	if d.loadIndex(idxPath, info) == nil {
+		return d, nil
+	}
+	if err := d.buildIndex(info.Size()); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	// A missing cache only costs a rebuild on the next start.
+	_ = d.saveIndex(idxPath, info)
+	return d, nil
+}
+
+func (d *fileDump) HashLen() int { return d.hashLen }
+func (d *fileDump) Close() error { return d.f.Close() }
+
+func (d *fileDump) Range(prefix string) ([]string, error) {
+	p, err := strconv.ParseUint(prefix, 16, 32)
+	if err != nil {
+		return nil, ErrBadPrefix
+	}
+	start, end := d.offsets[p], d.offsets[p+1]
+	buf := make([]byte, end-start)
+	if _, err := d.f.ReadAt(buf, int64(start)); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range bytes.Split(buf, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) > PrefixLen {
+			lines = append(lines, strings.ToUpper(string(line[PrefixLen:])))
+		}
+	}
+	return lines, nil
+}
+
+// buildIndex scans the dump once, checking that hashes are sorted and of
+// one length.
+func (d *fileDump) buildIndex(size int64) error {
+	d.offsets = make([]uint64, prefixes+1)
+	r := bufio.NewReaderSize(io.NewSectionReader(d.f, 0, size), 1<<20)
+	var offset uint64
+	next := 0 // first prefix whose offset is not yet known
+	for lineNo := 1; ; lineNo++ {
+		line, err := r.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			return fmt.Errorf("line %d is too long", lineNo)
+		}
+		if len(line) > 0 {
+			text := bytes.TrimSpace(line)
+			if len(text) > 0 {
+				hash, _, ok := bytes.Cut(text, []byte(":"))
+				if !ok || (len(hash) != SHA1Len && len(hash) != NTLMLen) {
+					return fmt.Errorf("line %d is not HASH:COUNT", lineNo)
+				}
+				if d.hashLen == 0 {
+					d.hashLen = len(hash)
+				} else if len(hash) != d.hashLen {
+					return fmt.Errorf("line %d mixes hash types", lineNo)
+				}
+				p, perr := strconv.ParseUint(string(hash[:PrefixLen]), 16, 32)
+				if perr != nil {
+					return fmt.Errorf("line %d: invalid hash", lineNo)
+				}
+				if int(p) < next-1 {
+					return fmt.Errorf("line %d: dump is not sorted by hash", lineNo)
+				}
+				for ; next <= int(p); next++ {
+					d.offsets[next] = offset
+				}
+			}
+			offset += uint64(len(line))
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if d.hashLen == 0 {
+		return errors.New("dump is empty")
+	}
+	for ; next <= prefixes; next++ {
+		d.offsets[next] = offset
+	}
+	return nil
+}
+
+// Index files hold the magic, the dump's size and modification time, the
+// hash length and the offsets, all little-endian.
+func (d *fileDump) saveIndex(path string, info os.FileInfo) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	w := bufio.NewWriter(tmp)
+	w.Write(indexMagic)
+	binary.Write(w, binary.LittleEndian, []uint64{uint64(info.Size()), uint64(info.ModTime().UnixNano()), uint64(d.hashLen)})
+	binary.Write(w, binary.LittleEndian, d.offsets)
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	// The index reveals nothing the dump does not.
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (d *fileDump) loadIndex(path string, info os.FileInfo) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	magic := make([]byte, len(indexMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || !bytes.Equal(magic, indexMagic) {
+		return errors.New("not an index file")
+	}
+	var header [3]uint64
+	if err := binary.Read(r, binary.LittleEndian, header[:]); err != nil {
+		return err
+	}
+	if header[0] != uint64(info.Size()) || header[1] != uint64(info.ModTime().UnixNano()) {
+		return errors.New("index is stale")
+	}
+	offsets := make([]uint64, prefixes+1)
+	if err := binary.Read(r, binary.LittleEndian, offsets); err != nil {
+		return err
+	}
+	d.hashLen, d.offsets = int(header[2]), offsets
+	return nil
+}
+
+// dirDump serves per-prefix files written by the downloader's split mode.
+type dirDump struct {
+	dir     string
+	hashLen int
+}
+
+func openDir(dir string) (*dirDump, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "?????.txt"))
+	if err != nil || len(matches) == 0 {
+		return nil, fmt.Errorf("%s holds no <PREFIX>.txt range files", dir)
+	}
+	d := &dirDump{dir: dir}
+	lines, err := d.Range(strings.ToUpper(strings.TrimSuffix(filepath.Base(matches[0]), ".txt")))
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%s is empty", matches[0])
+	}
+	suffix, _, _ := strings.Cut(lines[0], ":")
+	switch d.hashLen = len(suffix) + PrefixLen; d.hashLen {
+	case SHA1Len, NTLMLen:
+		return d, nil
+	}
+	return nil, fmt.Errorf("%s: unrecognised hash length %d", matches[0], d.hashLen)
+}
+
+func (d *dirDump) HashLen() int { return d.hashLen }
+func (d *dirDump) Close() error { return nil }
+
+func (d *dirDump) Range(prefix string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(d.dir, prefix+".txt"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, strings.ToUpper(line))
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,183 @@
+package pwnrange
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// breached are the passwords of the test mirror and their counts.
+var breached = map[string]int{
+	"password":  9545824,
+	"123456":    42033000,
+	"letmein":   412331,
+	"P@ssw0rd!": 2519,
+	"hunter2":   23547,
+}
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// dumpLines returns the "HASH:COUNT" lines of breached, sorted by hash.
+func dumpLines() []string {
+	var lines []string
+	for pw, count := range breached {
+		lines = append(lines, fmt.Sprintf("%s:%d", sha1Hex(pw), count))
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+// writeDump writes the mirror as a single sorted file with CRLF line ends,
+// as the official downloader does.
+func writeDump(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pwned-passwords-sha1.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(dumpLines(), "\r\n")+"\r\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writeDir writes the mirror as per-prefix range files.
+func writeDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	ranges := map[string][]string{}
+	for _, line := range dumpLines() {
+		ranges[line[:PrefixLen]] = append(ranges[line[:PrefixLen]], line[PrefixLen:])
+	}
+	for prefix, lines := range ranges {
+		if err := os.WriteFile(filepath.Join(dir, prefix+".txt"), []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRange(t *testing.T) {
+	for name, path := range map[string]string{"file": writeDump(t), "dir": writeDir(t)} {
+		t.Run(name, func(t *testing.T) {
+			d, err := Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer d.Close()
+			if d.HashLen() != SHA1Len {
+				t.Errorf("HashLen = %d", d.HashLen())
+			}
+			for pw, count := range breached {
+				hash := sha1Hex(pw)
+				lines, err := d.Range(hash[:PrefixLen])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want := fmt.Sprintf("%s:%d", hash[PrefixLen:], count); !slices.Contains(lines, want) {
+					t.Errorf("Range(%s) = %q, want it to contain %s", hash[:PrefixLen], lines, want)
+				}
+			}
+			if lines, err := d.Range("00000"); err != nil || len(lines) != 0 {
+				t.Errorf("Range of an empty prefix = %q, %v", lines, err)
+			}
+		})
+	}
+}
+
+func TestIndexCache(t *testing.T) {
+	path := writeDump(t)
+	d, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+	if _, err := os.Stat(path + ".idx"); err != nil {
+		t.Fatalf("index was not cached: %v", err)
+	}
+
+	// A changed dump invalidates the cached index.
+	hash := sha1Hex("correct horse battery staple")
+	lines := append(dumpLines(), hash+":7")
+	slices.Sort(lines)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	d, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	got, err := d.Range(hash[:PrefixLen])
+	if err != nil || !slices.Contains(got, hash[PrefixLen:]+":7") {
+		t.Errorf("Range after the dump changed = %q, %v", got, err)
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	lines := dumpLines()
+	unsorted := append([]string{lines[len(lines)-1]}, lines[:len(lines)-1]...)
+	tests := map[string]string{
+		strings.Join(unsorted, "\n"):                          "line 2: dump is not sorted by hash",
+		lines[0] + "\n" + strings.Repeat("A", NTLMLen) + ":1": "line 2 mixes hash types",
+		"not a dump\n": "line 1 is not HASH:COUNT",
+		"\n\n":         "dump is empty",
+	}
+	for content, want := range tests {
+		path := filepath.Join(t.TempDir(), "dump.txt")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Open(path); err == nil || !strings.HasSuffix(err.Error(), want) {
+			t.Errorf("Open(%.20q) = %v, want %s", content, err, want)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	for _, hashLen := range []int{SHA1Len, NTLMLen} {
+		real := []string{strings.Repeat("A", hashLen-PrefixLen) + ":3"}
+		padded := Pad(slices.Clone(real), hashLen)
+		if len(padded) < minPadded || len(padded) > maxPadded {
+			t.Errorf("Pad returned %d entries, want %d to %d", len(padded), minPadded, maxPadded)
+		}
+		if padded[0] != real[0] {
+			t.Errorf("Pad changed the real entry to %s", padded[0])
+		}
+		for _, line := range padded[1:] {
+			suffix, count, _ := strings.Cut(line, ":")
+			if len(suffix) != hashLen-PrefixLen || count != "0" {
+				t.Errorf("padding entry %q, want a %d-character suffix with count 0", line, hashLen-PrefixLen)
+				break
+			}
+		}
+	}
+	// Ranges already past the target are left alone.
+	big := make([]string, maxPadded+5)
+	if got := Pad(big, SHA1Len); len(got) != len(big) {
+		t.Errorf("Pad grew a %d-entry range to %d", len(big), len(got))
+	}
+}
+
+func TestNormalizePrefix(t *testing.T) {
+	for prefix, want := range map[string]string{"abcde": "ABCDE", "0F9a1": "0F9A1"} {
+		if got, err := NormalizePrefix(prefix); got != want || err != nil {
+			t.Errorf("NormalizePrefix(%q) = %q, %v", prefix, got, err)
+		}
+	}
+	for _, prefix := range []string{"", "ABCD", "ABCDEF", "ABCDG", "+1234", "-1234"} {
+		if _, err := NormalizePrefix(prefix); err != ErrBadPrefix {
+			t.Errorf("NormalizePrefix(%q) = %v, want ErrBadPrefix", prefix, err)
+		}
+	}
+}
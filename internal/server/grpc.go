@@ -0,0 +1,221 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCServer serves gRPC services with the same authentication, rate
+// limiting and logging as the HTTP server. Tokens travel in the
+// "authorization" metadata as "Bearer <token>".
+type GRPCServer struct {
+	base
+	grpc *grpc.Server
+}
+
+// NewGRPC creates a gRPC server; register adds the services to it.
+func NewGRPC(opts Options, register func(*grpc.Server)) (*GRPCServer, error) {
+	b, err := newBase(opts)
+	if err != nil {
+		return nil, err
+	}
+	s := &GRPCServer{base: b}
+	serverOpts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(MaxBodyBytes),
+		grpc.ChainUnaryInterceptor(s.unary),
+		grpc.ChainStreamInterceptor(s.stream),
+	}
+	if s.tls != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(s.tls)))
+	}
+	s.grpc = grpc.NewServer(serverOpts...)
+	register(s.grpc)
+	return s, nil
+}
+
+// Run serves until ctx is cancelled, then lets in-flight calls finish for
+// up to ShutdownTimeout before closing the remaining ones.
+func (s *GRPCServer) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.opts.Addr)
+	if err != nil {
+		return err
+	}
+	s.log.Info("listening", "addr", ln.Addr().String(), "tls", s.tls != nil, "mtls", s.opts.ClientCA != "", "protocol", "grpc")
+
+	errc := make(chan error, 1)
+	go func() { errc <- s.grpc.Serve(ln) }()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	s.log.Info("shutting down")
+	timeout := s.opts.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		s.grpc.Stop()
+		return errors.New("shutdown timed out; in-flight calls were cancelled")
+	}
+	if err := <-errc; err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// ClientFromContext is Client for gRPC handlers.
+func ClientFromContext(ctx context.Context) string {
+	var addr string
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+	}
+	return clientFromContext(ctx, addr)
+}
+
+// authorize authenticates a call and returns a context carrying the
+// client identity. Failed attempts count against the caller's address.
+func (s *GRPCServer) authorize(ctx context.Context) (context.Context, error) {
+	if s.opts.Public {
+		return ctx, nil
+	}
+	var state *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			state = &info.State
+		}
+	}
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			authorization = v[0]
+		}
+	}
+	client := s.identify(state, authorization)
+	if client == "" {
+		if err := s.allow(ctx); err != nil {
+			return nil, err
+		}
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid credentials")
+	}
+	return context.WithValue(ctx, clientKey{}, client), nil
+}
+
+// allow takes a rate limit token for the caller.
+func (s *GRPCServer) allow(ctx context.Context) error {
+	if s.limiter == nil {
+		return nil
+	}
+	if wait, ok := s.limiter.allow(ClientFromContext(ctx), time.Now()); !ok {
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded; retry in %s", wait.Round(time.Millisecond))
+	}
+	return nil
+}
+
+// wait blocks until the caller may send another message. Streams are
+// throttled rather than failed, so flow control pushes back on clients.
+func (s *GRPCServer) wait(ctx context.Context) error {
+	if s.limiter == nil {
+		return nil
+	}
+	client := ClientFromContext(ctx)
+	for {
+		wait, ok := s.limiter.allow(client, time.Now())
+		if ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (s *GRPCServer) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	start := time.Now()
+	defer func() {
+		if v := recover(); v != nil {
+			s.log.Error("handler panic", "method", info.FullMethod, "panic", fmt.Sprint(v))
+			err = status.Error(codes.Internal, "internal error")
+		}
+		s.logCall(ctx, info.FullMethod, err, start, 1)
+	}()
+	authCtx, err := s.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = authCtx
+	if err := s.allow(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *GRPCServer) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	start := time.Now()
+	ctx := ss.Context()
+	wrapped := &limitedStream{ServerStream: ss, s: s}
+	defer func() {
+		if v := recover(); v != nil {
+			s.log.Error("handler panic", "method", info.FullMethod, "panic", fmt.Sprint(v))
+			err = status.Error(codes.Internal, "internal error")
+		}
+		s.logCall(ctx, info.FullMethod, err, start, wrapped.received)
+	}()
+	authCtx, err := s.authorize(ctx)
+	if err != nil {
+		return err
+	}
+	ctx = authCtx
+	wrapped.ctx = ctx
+	return handler(srv, wrapped)
+}
+
+// limitedStream applies the rate limit to every received message.
+type limitedStream struct {
+	grpc.ServerStream
+	s        *GRPCServer
+	ctx      context.Context
+	received int
+}
+
+func (l *limitedStream) Context() context.Context { return l.ctx }
+
+func (l *limitedStream) RecvMsg(m any) error {
+	if err := l.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	l.received++
+	return l.s.wait(l.ctx)
+}
+
+// logCall logs one line per call, like the HTTP access log: never
+// messages or metadata.
+func (s *GRPCServer) logCall(ctx context.Context, method string, err error, start time.Time, messages int) {
+	s.log.Info("rpc",
+		"method", method,
+		"code", status.Code(err).String(),
+		"messages", messages,
+		"duration_ms", time.Since(start).Milliseconds(),
+		"client", ClientFromContext(ctx),
+	)
+}
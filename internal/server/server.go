@@ -0,0 +1,347 @@
+// Package server runs PwdForge's HTTP and gRPC APIs: authentication,
+// per-client rate limiting, access logging and graceful shutdown around
+// handlers supplied by the caller.
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxBodyBytes caps request bodies; API requests are small JSON objects.
+const MaxBodyBytes = 64 << 10
+
+type Options struct {
+	Addr string
+	// Tokens are accepted as "Authorization: Bearer <token>".
+	Tokens []string
+	// Public serves the handler without authentication. Only endpoints that
+	// hand out no secrets, such as breach range queries, may be public.
+	Public bool
+	// TLSCert and TLSKey enable HTTPS. ClientCA additionally requires client
+	// certificates signed by that CA (mTLS), which then authenticate clients.
+	TLSCert, TLSKey, ClientCA string
+	// Rate is the sustained requests per second allowed per client, Burst
+	// the bucket size. A Rate of 0 disables limiting.
+	Rate  float64
+	Burst int
+	// ShutdownTimeout bounds how long in-flight requests may finish.
+	ShutdownTimeout time.Duration
+	Logger          *slog.Logger
+}
+
+// base is the state shared by the HTTP and gRPC servers.
+type base struct {
+	opts    Options
+	tokens  [][32]byte
+	limiter *limiter
+	log     *slog.Logger
+	tls     *tls.Config // nil without TLS
+}
+
+type Server struct {
+	base
+	http *http.Server
+}
+
+// newBase checks opts and loads the TLS material. Unless Public is set, at
+// least one of Tokens or ClientCA must be: the API hands out secrets and is
+// never anonymous.
+func newBase(opts Options) (base, error) {
+	if !opts.Public && len(opts.Tokens) == 0 && opts.ClientCA == "" {
+		return base{}, errors.New("configure bearer tokens or a client CA; the API does not run without authentication")
+	}
+	if (opts.TLSCert == "") != (opts.TLSKey == "") {
+		return base{}, errors.New("TLS needs both a certificate and a key")
+	}
+	if opts.ClientCA != "" && opts.TLSCert == "" {
+		return base{}, errors.New("client certificate authentication needs a TLS certificate and key")
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	}
+	b := base{opts: opts, log: opts.Logger}
+	for _, t := range opts.Tokens {
+		b.tokens = append(b.tokens, sha256.Sum256([]byte(t)))
+	}
+	if opts.Rate > 0 {
+		b.limiter = newLimiter(opts.Rate, max(opts.Burst, 1))
+	}
+	if opts.TLSCert == "" {
+		return b, nil
+	}
+	cert, err := tls.LoadX509KeyPair(opts.TLSCert, opts.TLSKey)
+	if err != nil {
+		return base{}, err
+	}
+	b.tls = &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{cert}}
+	if opts.ClientCA != "" {
+		pem, err := os.ReadFile(opts.ClientCA)
+		if err != nil {
+			return base{}, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return base{}, fmt.Errorf("no certificates found in %s", opts.ClientCA)
+		}
+		b.tls.ClientCAs = pool
+		// With tokens configured too, either credential is enough.
+		b.tls.ClientAuth = tls.RequireAndVerifyClientCert
+		if len(opts.Tokens) > 0 {
+			b.tls.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+	return b, nil
+}
+
+// New wraps handler with the API middleware.
+func New(opts Options, handler http.Handler) (*Server, error) {
+	b, err := newBase(opts)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{base: b}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	if opts.Public {
+		mux.Handle("/", s.rateLimit(handler))
+	} else {
+		mux.Handle("/", s.authenticate(s.rateLimit(handler)))
+	}
+
+	s.http = &http.Server{
+		Addr:              opts.Addr,
+		Handler:           s.accessLog(recoverPanics(mux, s.log)),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		ErrorLog:          slog.NewLogLogger(s.log.Handler(), slog.LevelWarn),
+		TLSConfig:         s.tls,
+	}
+	return s, nil
+}
+
+// Run serves until ctx is cancelled, then stops accepting connections and
+// waits up to ShutdownTimeout for in-flight requests.
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.opts.Addr)
+	if err != nil {
+		return err
+	}
+	s.log.Info("listening", "addr", ln.Addr().String(), "tls", s.opts.TLSCert != "", "mtls", s.opts.ClientCA != "")
+
+	errc := make(chan error, 1)
+	go func() {
+		if s.tls != nil {
+			errc <- s.http.ServeTLS(ln, "", "")
+		} else {
+			errc <- s.http.Serve(ln)
+		}
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	s.log.Info("shutting down")
+	timeout := s.opts.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := s.http.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+type (
+	clientKey    struct{}
+	logClientKey struct{}
+)
+
+// Client returns the identity the request authenticated as: "cert:<CN>",
+// "token:<fingerprint>" or "ip:<address>". It is safe to log.
+func Client(r *http.Request) string {
+	return clientFromContext(r.Context(), r.RemoteAddr)
+}
+
+func clientFromContext(ctx context.Context, remoteAddr string) string {
+	if c, ok := ctx.Value(clientKey{}).(string); ok {
+		return c
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return "ip:" + host
+}
+
+// identify returns the identity proven by a verified client certificate or
+// a bearer token in the Authorization value, or "" for neither.
+func (b *base) identify(state *tls.ConnectionState, authorization string) string {
+	if state != nil && len(state.VerifiedChains) > 0 {
+		return "cert:" + state.VerifiedChains[0][0].Subject.CommonName
+	}
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.TrimPrefix(authorization, "Bearer ")))
+	match := 0
+	for _, t := range b.tokens {
+		match |= subtle.ConstantTimeCompare(sum[:], t[:])
+	}
+	if match == 1 {
+		return "token:" + hex.EncodeToString(sum[:4])
+	}
+	return ""
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := s.identify(r.TLS, r.Header.Get("Authorization"))
+		if client == "" {
+			// Failed attempts count against the caller's address, which
+			// slows down token guessing.
+			if s.limiter != nil {
+				if wait, ok := s.limiter.allow(Client(r), time.Now()); !ok {
+					w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+					WriteError(w, http.StatusTooManyRequests, "rate limit exceeded")
+					return
+				}
+			}
+			w.Header().Set("WWW-Authenticate", `Bearer realm="pwdforge"`)
+			WriteError(w, http.StatusUnauthorized, "missing or invalid credentials")
+			return
+		}
+		if p, ok := r.Context().Value(logClientKey{}).(*string); ok {
+			*p = client
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientKey{}, client)))
+	})
+}
+
+func (s *Server) rateLimit(next http.Handler) http.Handler {
+	if s.limiter == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wait, ok := s.limiter.allow(Client(r), time.Now()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+			WriteError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusRecorder captures the status and size of a response for logging.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// accessLog logs one line per request. Bodies, query strings and headers
+// are never logged, so secrets cannot leak into logs.
+func (s *Server) accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		r.Body = http.MaxBytesReader(rec, r.Body, MaxBodyBytes)
+		// The identity is only known after authentication, which fills in
+		// client through the context.
+		var client string
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), logClientKey{}, &client)))
+		if client == "" {
+			client = Client(r)
+		}
+		s.log.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"bytes", rec.bytes,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"client", client,
+		)
+	})
+}
+
+func recoverPanics(next http.Handler, log *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if v := recover(); v != nil {
+				log.Error("handler panic", "path", r.URL.Path, "panic", fmt.Sprint(v))
+				WriteError(w, http.StatusInternalServerError, "internal error")
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// DecodeJSON reads a JSON request body into v, rejecting unknown fields and
+// trailing data so typos in requests are reported instead of ignored.
+func DecodeJSON(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return fmt.Errorf("request body exceeds %d bytes", maxErr.Limit)
+		}
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	if dec.More() {
+		return errors.New("invalid JSON body: unexpected data after the object")
+	}
+	return nil
+}
+
+// WriteJSON writes v with the given status.
+func WriteJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// WriteError writes {"error": msg}.
+func WriteError(w http.ResponseWriter, status int, msg string) {
+	WriteJSON(w, status, map[string]string{"error": msg})
+}
@@ -1,29 +1,243 @@
 package clipboard
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"runtime"
 	"strings"
+	"time"
 )
 
+// HelperCommand is the hidden subcommand the detached clear helper runs as.
+const HelperCommand = "clipboard-clear"
+
+// SumEnv carries the SHA-256 of the copied secret to the clear helper, so the
+// secret itself never appears in the helper's arguments or environment.
+const SumEnv = "PWDFORGE_CLIPBOARD_SUM"
+
+// ErrNoBackend is returned when no clipboard mechanism could be found.
+var ErrNoBackend = errors.New("no clipboard backend available: install wl-clipboard (Wayland), xclip or xsel (X11), or run inside tmux or an OSC 52 capable terminal")
+
+// Backend is a clipboard mechanism available on this system.
+type Backend struct {
+	Name  string
+	copy  func(text string) error
+	paste func() (string, error)
+	clear func() error
+}
+
+// Copy places text on the clipboard.
+func (b *Backend) Copy(text string) error {
+	return b.copy(text)
+}
+
+// CanRead reports whether the backend can read the clipboard back. Backends
+// that cannot (OSC 52) are never cleared, since we could not tell whether
+// the clipboard still holds our secret.
+func (b *Backend) CanRead() bool {
+	return b.paste != nil
+}
+
+// Paste returns the current clipboard contents.
+func (b *Backend) Paste() (string, error) {
+	if b.paste == nil {
+		return "", fmt.Errorf("clipboard backend %s cannot read the clipboard", b.Name)
+	}
+	return b.paste()
+}
+
+// Detect picks the first usable clipboard backend for the current session.
+func Detect() (*Backend, error) {
+	for _, name := range candidates() {
+		if b := backendByName(name); b != nil {
+			return b, nil
+		}
+	}
+	return nil, ErrNoBackend
+}
+
+// Lookup returns the named backend, or an error if it is not usable here.
+func Lookup(name string) (*Backend, error) {
+	if b := backendByName(name); b != nil {
+		return b, nil
+	}
+	return nil, fmt.Errorf("clipboard backend %q is not available", name)
+}
+
 // CopyToClipboard copies the given text to the system clipboard.
 func CopyToClipboard(text string) error {
-	var cmd *exec.Cmd
+	b, err := Detect()
+	if err != nil {
+		return err
+	}
+	return b.Copy(text)
+}
+
+// Sum returns the hex SHA-256 of text, used to recognise our own secret.
+func Sum(text string) string {
+	h := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(h[:])
+}
+
+// ScheduleClear starts a detached helper process that clears the clipboard
+// after the given delay, but only if it still holds text. The helper is the
+// current executable invoked with HelperCommand.
+func ScheduleClear(b *Backend, text string, after time.Duration) error {
+	if after <= 0 {
+		return nil
+	}
+	if !b.CanRead() {
+		return fmt.Errorf("clipboard backend %s cannot be cleared safely", b.Name)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(exe, HelperCommand, "--backend", b.Name, "--after", after.String())
+	cmd.Env = append(os.Environ(), SumEnv+"="+Sum(text))
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
 
+// ClearIfUnchanged empties the clipboard if its contents still hash to sum.
+// It reports whether the clipboard was cleared.
+func ClearIfUnchanged(b *Backend, sum string) (bool, error) {
+	current, err := b.Paste()
+	if err != nil {
+		return false, err
+	}
+	// Some tools append a trailing newline on paste, "\r\n" on Windows.
+	if Sum(current) != sum && Sum(strings.TrimSuffix(current, "\r\n")) != sum && Sum(strings.TrimSuffix(current, "\n")) != sum {
+		return false, nil
+	}
+	if b.clear != nil {
+		return true, b.clear()
+	}
+	return true, b.Copy("")
+}
+
+func candidates() []string {
 	switch runtime.GOOS {
 	case "windows":
-		cmd = exec.Command("clip")
-		cmd.Stdin = strings.NewReader(text)
+		return []string{"windows"}
 	case "darwin":
-		cmd = exec.Command("pbcopy")
-		cmd.Stdin = strings.NewReader(text)
-	case "linux":
-		cmd = exec.Command("xclip", "-selection", "clipboard")
-		cmd.Stdin = strings.NewReader(text)
+		return []string{"pbcopy", "tmux", "osc52"}
 	default:
-		return fmt.Errorf("unsupported OS for clipboard operation")
+		return []string{"wl-clipboard", "xclip", "xsel", "tmux", "osc52"}
 	}
+}
+
+func backendByName(name string) *Backend {
+	switch name {
+	case "windows":
+		if !hasCommand("clip") {
+			return nil
+		}
+		return &Backend{
+			Name:  name,
+			copy:  pipeTo("clip"),
+			paste: output("powershell", "-NoProfile", "-Command", "Get-Clipboard -Raw"),
+		}
+	case "pbcopy":
+		if !hasCommand("pbcopy") {
+			return nil
+		}
+		return &Backend{Name: name, copy: pipeTo("pbcopy"), paste: output("pbpaste")}
+	case "wl-clipboard":
+		if os.Getenv("WAYLAND_DISPLAY") == "" || !hasCommand("wl-copy") {
+			return nil
+		}
+		b := &Backend{Name: name, copy: pipeTo("wl-copy"), clear: run("wl-copy", "--clear")}
+		if hasCommand("wl-paste") {
+			b.paste = output("wl-paste", "--no-newline")
+		}
+		return b
+	case "xclip":
+		if os.Getenv("DISPLAY") == "" || !hasCommand("xclip") {
+			return nil
+		}
+		return &Backend{
+			Name:  name,
+			copy:  pipeTo("xclip", "-selection", "clipboard"),
+			paste: output("xclip", "-selection", "clipboard", "-o"),
+		}
+	case "xsel":
+		if os.Getenv("DISPLAY") == "" || !hasCommand("xsel") {
+			return nil
+		}
+		return &Backend{
+			Name:  name,
+			copy:  pipeTo("xsel", "--clipboard", "--input"),
+			paste: output("xsel", "--clipboard", "--output"),
+		}
+	case "tmux":
+		if os.Getenv("TMUX") == "" || !hasCommand("tmux") {
+			return nil
+		}
+		// -w also forwards the buffer to the outer terminal via OSC 52.
+		// tmux ignores empty buffers, so clearing deletes ours instead.
+		return &Backend{
+			Name:  name,
+			copy:  pipeTo("tmux", "load-buffer", "-w", "-"),
+			paste: output("tmux", "save-buffer", "-"),
+			clear: run("tmux", "delete-buffer"),
+		}
+	case "osc52":
+		term := os.Getenv("TERM")
+		if term == "" || term == "dumb" {
+			return nil
+		}
+		tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+		if err != nil {
+			return nil
+		}
+		tty.Close()
+		return &Backend{Name: name, copy: osc52Copy}
+	}
+	return nil
+}
 
-	return cmd.Run()
+func hasCommand(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+func pipeTo(name string, args ...string) func(string) error {
+	return func(text string) error {
+		cmd := exec.Command(name, args...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+}
+
+func run(name string, args ...string) func() error {
+	return func() error {
+		return exec.Command(name, args...).Run()
+	}
+}
+
+func output(name string, args ...string) func() (string, error) {
+	return func() (string, error) {
+		out, err := exec.Command(name, args...).Output()
+		return string(out), err
+	}
+}
+
+// osc52Copy asks the terminal emulator to set its clipboard.
+func osc52Copy(text string) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+	_, err = fmt.Fprintf(tty, "\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
+	return err
 }
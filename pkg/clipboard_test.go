@@ -0,0 +1,48 @@
+package clipboard
+
+import "testing"
+
+// memoryBackend is a clipboard backend that pastes what was copied, with
+// suffix appended as some paste tools do.
+func memoryBackend(suffix string) *Backend {
+	var contents string
+	return &Backend{
+		Name:  "memory",
+		copy:  func(text string) error { contents = text; return nil },
+		paste: func() (string, error) { return contents + suffix, nil },
+	}
+}
+
+func TestClearIfUnchanged(t *testing.T) {
+	const secret = "Tr0ub4dor&3"
+	for _, suffix := range []string{"", "\n", "\r\n"} {
+		b := memoryBackend(suffix)
+		if err := b.Copy(secret); err != nil {
+			t.Fatal(err)
+		}
+		cleared, err := ClearIfUnchanged(b, Sum(secret))
+		if err != nil || !cleared {
+			t.Errorf("paste suffix %q: ClearIfUnchanged = %v, %v, want cleared", suffix, cleared, err)
+		}
+		if got, _ := b.Paste(); got != suffix {
+			t.Errorf("paste suffix %q: clipboard holds %q after clearing", suffix, got)
+		}
+	}
+}
+
+func TestClearIfUnchangedKeepsOtherContents(t *testing.T) {
+	const secret = "Tr0ub4dor&3"
+	for _, other := range []string{"something else", secret + "x", secret + "\n\n", secret[:len(secret)-1]} {
+		b := memoryBackend("")
+		if err := b.Copy(other); err != nil {
+			t.Fatal(err)
+		}
+		cleared, err := ClearIfUnchanged(b, Sum(secret))
+		if err != nil || cleared {
+			t.Errorf("clipboard %q: ClearIfUnchanged = %v, %v, want it kept", other, cleared, err)
+		}
+		if got, _ := b.Paste(); got != other {
+			t.Errorf("clipboard %q was changed to %q", other, got)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package clipboard
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the helper in its own session so it outlives the terminal.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,149 @@
+package pwdforge
+
+import (
+	"context"
+	"errors"
+
+	"pwdforge/internal/generator"
+	"pwdforge/internal/hasher"
+	"pwdforge/internal/policy"
+	"pwdforge/internal/pwnchecker"
+)
+
+// Strength is a password's rating.
+type Strength struct {
+	// Rating is "Weak", "Medium", "Strong" or "Very Strong".
+	Rating  string
+	Entropy float64
+	// Suggestions say how to improve the password.
+	Suggestions []string
+}
+
+// CheckStrength rates password. Passwords containing a banned word, in
+// any case or l33t spelling, are rated Weak; banned may be nil.
+//
+//	s := pwdforge.CheckStrength("Acme-2024!", pwdforge.NewBlocklist("acme"))
+//	fmt.Println(s.Rating) // Weak
+func CheckStrength(password string, banned *Blocklist) Strength {
+	rating, entropy, suggestions := generator.CheckPasswordStrengthWith(password, banned)
+	return Strength{Rating: rating, Entropy: entropy, Suggestions: suggestions}
+}
+
+// DefaultBreachAPIURL is the public Pwned Passwords range API.
+const DefaultBreachAPIURL = pwnchecker.DefaultAPIURL
+
+// BreachChecker looks passwords up in a Pwned Passwords range API. Only the
+// first five characters of the password's SHA-1 hash leave the process.
+type BreachChecker struct {
+	// APIURL is the base URL of the range API, such as a mirror run with
+	// "pwdforge serve-range". Empty means DefaultBreachAPIURL.
+	APIURL string
+}
+
+// Check reports whether password appears in the breach corpus and how
+// often.
+func (b BreachChecker) Check(ctx context.Context, password string) (bool, int, error) {
+	return b.CheckSHA1(ctx, pwnchecker.SHA1Hex(password))
+}
+
+// CheckSHA1 is Check for the hex SHA-1 of a password.
+func (b BreachChecker) CheckSHA1(ctx context.Context, sha1Hex string) (bool, int, error) {
+	url := b.APIURL
+	if url == "" {
+		url = DefaultBreachAPIURL
+	}
+	pwned, count, err := pwnchecker.CheckHashPwnedAt(ctx, url, sha1Hex)
+	if err != nil {
+		return false, 0, &BreachCheckError{Err: err}
+	}
+	return pwned, count, nil
+}
+
+// CheckPwned checks password against the public Pwned Passwords API.
+//
+//	pwned, count, err := pwdforge.CheckPwned(ctx, "P@ssw0rd")
+func CheckPwned(ctx context.Context, password string) (bool, int, error) {
+	return BreachChecker{}.Check(ctx, password)
+}
+
+// PolicyOptions carries the context of a policy check.
+type PolicyOptions struct {
+	// Username is checked by the policy's disallow_username rule.
+	Username string
+	// Entropy is the password's exact entropy, such as Secret.Entropy, for
+	// the min_entropy rule; 0 estimates it from the password.
+	Entropy float64
+	// Breaches answers the policy's not_pwned rule; the zero value uses the
+	// public API.
+	Breaches BreachChecker
+}
+
+// CheckPolicy checks password against every rule of pol. Passed reports
+// whether all of them passed.
+//
+//	pol, _ := pwdforge.LookupPreset("nist-800-63b")
+//	results, err := pwdforge.CheckPolicy(ctx, pol, "correct horse battery", pwdforge.PolicyOptions{Username: "alice"})
+//	if err == nil && !pwdforge.Passed(results) {
+//		fmt.Println("rejected:", pwdforge.Failed(results))
+//	}
+func CheckPolicy(ctx context.Context, pol *Policy, password string, opts PolicyOptions) ([]PolicyResult, error) {
+	if err := pol.Validate(); err != nil {
+		return nil, &OptionError{Option: "policy", Err: err}
+	}
+	// Prepare caches word lists in the policy; keep callers' copy unchanged.
+	p := *pol
+	if err := p.Prepare(); err != nil {
+		return nil, &OptionError{Option: "policy", Err: err}
+	}
+	results, err := p.Check(password, policy.Options{
+		Username: opts.Username,
+		Entropy:  opts.Entropy,
+		PwnCheck: func(pw string) (bool, int, error) { return opts.Breaches.Check(ctx, pw) },
+	})
+	var breachErr *BreachCheckError
+	if errors.As(err, &breachErr) {
+		return nil, breachErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Passed reports whether every result passed.
+func Passed(results []PolicyResult) bool { return policy.Passed(results) }
+
+// Failed returns the names of the rules that failed.
+func Failed(results []PolicyResult) []string { return policy.Failed(results) }
+
+// LookupPreset returns a copy of a built-in policy preset; see PresetNames.
+func LookupPreset(name string) (*Policy, error) { return policy.LookupPreset(name) }
+
+// Strictest combines two policies into one that passes only passwords
+// both accept; either may be nil.
+func Strictest(a, b *Policy) *Policy { return policy.Strictest(a, b) }
+
+// PresetNames lists the built-in policy presets.
+func PresetNames() []string { return policy.PresetNames() }
+
+// ImportADPolicy reads an Active Directory password policy from a secedit
+// INF export or an LDIF export of the domain or fine-grained policies.
+func ImportADPolicy(path string) (*Policy, error) { return policy.ParseFile(path) }
+
+// HashAlgorithms lists the algorithms Hash supports.
+func HashAlgorithms() []string { return append([]string(nil), hasher.Algorithms...) }
+
+// Hash returns password hashed with algorithm in its standard encoding,
+// e.g. "$argon2id$v=19$..." or a crypt(3) string.
+//
+//	h, err := pwdforge.Hash("argon2id", password)
+func Hash(algorithm, password string) (string, error) { return hasher.Hash(algorithm, password) }
+
+// IdentifyHash names the algorithm of an encoded hash, such as "argon2id"
+// or "sha512crypt", or returns ErrUnsupportedHash.
+func IdentifyHash(encoded string) (string, error) { return hasher.Identify(encoded) }
+
+// VerifyHash reports whether password matches encoded, whose algorithm is
+// detected from its format. Unknown formats return ErrUnsupportedHash, and
+// malformed hashes or cost parameters beyond sane limits return an error.
+func VerifyHash(encoded, password string) (bool, error) { return hasher.Verify(encoded, password) }
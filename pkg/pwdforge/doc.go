@@ -0,0 +1,55 @@
+// Package pwdforge is the Go library behind the pwdforge CLI: password,
+// passphrase, PIN and token generation, strength estimates, policy checks,
+// breach lookups and password hashing.
+//
+// Generate twenty-character passwords with the CLI defaults:
+//
+//	opts := pwdforge.DefaultOptions()
+//	opts.Length = 20
+//	opts.Count = 5
+//	secrets, err := pwdforge.Generate(ctx, opts)
+//	if err != nil {
+//		return err
+//	}
+//	for _, s := range secrets {
+//		fmt.Println(s.Value)
+//	}
+//
+// Stream yields the same secrets one at a time, for batches of millions:
+//
+//	opts.Count = 10_000_000
+//	for s, err := range pwdforge.Stream(ctx, opts) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Fprintln(w, s.Value)
+//	}
+//
+// Generate passwords that satisfy a built-in policy preset and never
+// contain the account's username:
+//
+//	pol, err := pwdforge.LookupPreset("pci-dss-4")
+//	if err != nil {
+//		return err
+//	}
+//	opts := pwdforge.DefaultOptions()
+//	opts.Policy = pol
+//	opts.Context = map[string]string{"user": "alice"}
+//	secrets, err := pwdforge.Generate(ctx, opts)
+//
+// Check a password a user chose:
+//
+//	s := pwdforge.CheckStrength("Summer2024!", nil)
+//	pwned, count, err := pwdforge.CheckPwned(ctx, "Summer2024!")
+//
+// Errors are typed: invalid options are *OptionError, unsatisfiable
+// policies *PolicyError, failed breach lookups *BreachCheckError, and an
+// empty character set is ErrEmptyCharset. Nothing in the package panics on
+// bad input.
+//
+// The package follows semantic versioning under Version: within a major
+// version, exported identifiers are not removed or changed incompatibly.
+package pwdforge
+
+// Version is the version of this API.
+const Version = "1.1.0"
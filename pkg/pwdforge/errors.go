@@ -0,0 +1,54 @@
+package pwdforge
+
+import (
+	"fmt"
+	"strings"
+
+	"pwdforge/internal/generator"
+	"pwdforge/internal/hasher"
+)
+
+var (
+	// ErrEmptyCharset means the selected classes and exclusions leave no
+	// characters to generate from.
+	ErrEmptyCharset = generator.ErrEmptyCharset
+	// ErrUnsupportedHash means VerifyHash does not recognise a hash format.
+	ErrUnsupportedHash = hasher.ErrUnsupportedHash
+)
+
+// OptionError reports an invalid or contradictory setting in Options.
+type OptionError struct {
+	// Option is the option's config key, e.g. "length" or "pattern".
+	Option string
+	Err    error
+}
+
+func (e *OptionError) Error() string {
+	return e.Option + ": " + e.Err.Error()
+}
+
+func (e *OptionError) Unwrap() error { return e.Err }
+
+// PolicyError means no candidate satisfied the policy within the attempt
+// budget, usually because the options cannot produce compliant passwords.
+type PolicyError struct {
+	Attempts int
+	// Failing lists the rules the last rejected candidate failed.
+	Failing []string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("no password satisfying the policy after %d attempts (failing: %s); adjust the generation options",
+		e.Attempts, strings.Join(e.Failing, ", "))
+}
+
+// BreachCheckError wraps a failed HaveIBeenPwned lookup.
+type BreachCheckError struct {
+	Err error
+}
+
+func (e *BreachCheckError) Error() string {
+	return "breach check failed: " + e.Err.Error()
+}
+
+func (e *BreachCheckError) Unwrap() error { return e.Err }
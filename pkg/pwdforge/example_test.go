@@ -0,0 +1,161 @@
+package pwdforge_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"pwdforge/pkg/pwdforge"
+)
+
+func ExampleGenerate() {
+	ctx := context.Background()
+	opts := pwdforge.DefaultOptions()
+	opts.Length = 20
+	opts.Count = 3
+	secrets, err := pwdforge.Generate(ctx, opts)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, s := range secrets {
+		fmt.Printf("%d characters, %.1f bits\n", len(s.Value), s.Entropy)
+	}
+	// Output:
+	// 20 characters, 129.5 bits
+	// 20 characters, 129.5 bits
+	// 20 characters, 129.5 bits
+}
+
+func ExampleGenerate_pattern() {
+	secrets, err := pwdforge.Generate(context.Background(), pwdforge.Options{
+		Pattern: "LLLL-DDDD",
+		Count:   2,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, s := range secrets {
+		fmt.Println(len(s.Value), s.Value[4] == '-')
+	}
+	// Output:
+	// 9 true
+	// 9 true
+}
+
+func ExampleGenerate_policy() {
+	ctx := context.Background()
+	pol, err := pwdforge.LookupPreset("pci-dss-4")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	opts := pwdforge.DefaultOptions()
+	opts.Policy = pol
+	opts.Context = map[string]string{"user": "alice"}
+	secrets, err := pwdforge.Generate(ctx, opts)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	results, err := pwdforge.CheckPolicy(ctx, pol, secrets[0].Value, pwdforge.PolicyOptions{})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(pwdforge.Passed(results), strings.Contains(strings.ToLower(secrets[0].Value), "alice"))
+	// Output: true false
+}
+
+func ExampleStream() {
+	opts := pwdforge.Options{PIN: true, Count: 1000}
+	n := 0
+	for s, err := range pwdforge.Stream(context.Background(), opts) {
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if utf8.RuneCountInString(s.Value) == pwdforge.DefaultPINLength {
+			n++
+		}
+	}
+	fmt.Println(n, "PINs")
+	// Output: 1000 PINs
+}
+
+func ExampleOptions_Validate() {
+	err := pwdforge.Options{Type: "hex", Bytes: -1}.Validate()
+	var oerr *pwdforge.OptionError
+	fmt.Println(errors.As(err, &oerr), oerr.Option)
+	// Output: true bytes
+}
+
+func ExampleCheckStrength() {
+	s := pwdforge.CheckStrength("Acme-2024!", pwdforge.NewBlocklist("acme"))
+	fmt.Println(s.Rating)
+	// Output: Weak
+}
+
+func ExampleCheckPolicy() {
+	pol, err := pwdforge.LookupPreset("nist-800-63b")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	ctx := context.Background()
+	for _, pw := range []string{"correct horse battery staple", "alice-password-2024"} {
+		results, err := pwdforge.CheckPolicy(ctx, pol, pw, pwdforge.PolicyOptions{Username: "alice"})
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(pwdforge.Passed(results), pwdforge.Failed(results))
+	}
+	// Output:
+	// true []
+	// false [banned_words disallow_username]
+}
+
+// CheckPwned needs network access, so this example is not run.
+func ExampleCheckPwned() {
+	pwned, count, err := pwdforge.CheckPwned(context.Background(), "P@ssw0rd")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(pwned, count > 0)
+}
+
+func ExampleHash() {
+	h, err := pwdforge.Hash("argon2id", "correct horse")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	ok, err := pwdforge.VerifyHash(h, "correct horse")
+	fmt.Println(strings.HasPrefix(h, "$argon2id$"), ok, err)
+	// Output: true true <nil>
+}
+
+func ExampleVerifyHash() {
+	ok, err := pwdforge.VerifyHash("$argon2id$v=19$m=65536,t=3,p=0$c2FsdHNhbHQ$aGFzaGhhc2g", "secret")
+	fmt.Println(ok, err)
+	_, err = pwdforge.VerifyHash("plaintext", "secret")
+	fmt.Println(errors.Is(err, pwdforge.ErrUnsupportedHash))
+	// Output:
+	// false invalid argon2id parallelism p=0
+	// true
+}
+
+func ExampleRender() {
+	rendered, err := pwdforge.Render("json", `pa"ss\word`)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(rendered)
+	// Output: "pa\"ss\\word"
+}
@@ -0,0 +1,490 @@
+package pwdforge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+
+	"pwdforge/internal/generator"
+	"pwdforge/internal/policy"
+)
+
+// maxPolicyAttempts bounds how many candidates Generate tries per
+// password before concluding the options cannot meet the policy.
+const maxPolicyAttempts = 1000
+
+// Secret is one generated secret.
+type Secret struct {
+	Value string
+	// Entropy is the exact entropy in bits for modes that know it, and 0
+	// otherwise; CheckStrength estimates it for any password.
+	Entropy float64
+}
+
+// Generate returns opts.Count secrets (at least one) in the mode opts
+// selects. A cancelled ctx stops generation between candidates, which
+// matters for policies that reject many candidates or look passwords up in
+// HaveIBeenPwned. Use Stream for batches too large to hold in memory.
+//
+//	secrets, err := pwdforge.Generate(ctx, pwdforge.Options{
+//		Pattern: "LLLL-DDDD-SSSS",
+//		Count:   3,
+//	})
+func Generate(ctx context.Context, opts Options) ([]Secret, error) {
+	var secrets []Secret
+	for s, err := range Stream(ctx, opts) {
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, s)
+	}
+	return secrets, nil
+}
+
+// Stream is Generate one secret at a time: options are checked once and
+// each secret is generated only when the loop asks for it, so memory use
+// does not grow with opts.Count. An error is yielded at most once, with a
+// zero Secret, and ends the sequence.
+//
+//	w := bufio.NewWriter(f)
+//	for s, err := range pwdforge.Stream(ctx, opts) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Fprintln(w, s.Value)
+//	}
+//	return w.Flush()
+func Stream(ctx context.Context, opts Options) iter.Seq2[Secret, error] {
+	return func(yield func(Secret, error) bool) {
+		if err := opts.normalize(); err != nil {
+			yield(Secret{}, err)
+			return
+		}
+		next, err := newSource(ctx, opts)
+		if err != nil {
+			yield(Secret{}, err)
+			return
+		}
+		for range opts.Count {
+			if err := ctx.Err(); err != nil {
+				yield(Secret{}, err)
+				return
+			}
+			value, entropy, err := next()
+			if err != nil {
+				yield(Secret{}, err)
+				return
+			}
+			if !yield(Secret{Value: value, Entropy: entropy}, nil) {
+				return
+			}
+		}
+	}
+}
+
+// source returns the next secret and its exact entropy, or 0 when the mode
+// does not know it.
+type source func() (value string, entropy float64, err error)
+
+// newSource checks the normalized opts and returns their secret source.
+func newSource(ctx context.Context, opts Options) (source, error) {
+	if opts.Policy != nil {
+		return policySource(ctx, opts)
+	}
+	return modeSource(ctx, opts)
+}
+
+// Resolved returns opts with the defaults Generate would use filled in,
+// or an *OptionError for out-of-range values. Context stays separate from
+// Policy.
+func (o Options) Resolved() (Options, error) {
+	err := o.resolve()
+	return o, err
+}
+
+// Validate reports the error Generate would return for opts before
+// generating anything: out-of-range values, unknown names, patterns and
+// charsets that do not parse, and policies the mode cannot satisfy. It
+// generates nothing and makes no network requests.
+func (o Options) Validate() error {
+	if err := o.normalize(); err != nil {
+		return err
+	}
+	_, err := newSource(context.Background(), o)
+	return err
+}
+
+// normalize resolves the defaults and merges Context into Policy.
+func (o *Options) normalize() error {
+	if err := o.resolve(); err != nil {
+		return err
+	}
+	if len(o.Context) > 0 {
+		banned := generator.NewBlocklist()
+		for _, value := range o.Context {
+			banned.AddContext(value)
+		}
+		o.Policy = policy.Strictest(o.Policy, &policy.Policy{Blocklist: banned})
+	}
+	if o.Policy != nil {
+		if err := o.Policy.Validate(); err != nil {
+			return &OptionError{Option: "policy", Err: err}
+		}
+	}
+	return nil
+}
+
+// resolve fills in defaults and rejects out-of-range values.
+func (o *Options) resolve() error {
+	switch {
+	case o.Length < 0:
+		return &OptionError{Option: "length", Err: errors.New("must not be negative")}
+	case o.Count < 0:
+		return &OptionError{Option: "count", Err: errors.New("must not be negative")}
+	case o.Bytes < 0:
+		return &OptionError{Option: "bytes", Err: errors.New("must not be negative")}
+	case o.WordCount < 0:
+		return &OptionError{Option: "word_count", Err: errors.New("must not be negative")}
+	case o.Type != "" && o.Type != "password" && !slices.Contains(generator.TokenTypes, o.Type):
+		return &OptionError{Option: "type", Err: fmt.Errorf("unknown type %q (want password, %s)", o.Type, strings.Join(generator.TokenTypes, ", "))}
+	}
+	if o.Count == 0 {
+		o.Count = 1
+	}
+	if o.Length == 0 {
+		o.Length = DefaultLength
+		if o.PIN {
+			o.Length = DefaultPINLength
+		}
+	}
+	if o.Bytes == 0 {
+		o.Bytes = DefaultTokenBytes
+	}
+	if o.WordCount == 0 {
+		o.WordCount = DefaultWordCount
+	}
+	return nil
+}
+
+// modeSource returns the source for the mode opts selects, ignoring any
+// policy. Setup such as parsing patterns and charsets happens once here
+// rather than per secret.
+func modeSource(ctx context.Context, opts Options) (source, error) {
+	exclude, err := exclusionSet(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mode := opts.Mode(); {
+	case slices.Contains(generator.TokenTypes, mode):
+		tcfg := generator.TokenConfig{Type: opts.Type, Bytes: opts.Bytes, Prefix: opts.KeyPrefix, Rand: opts.Rand}
+		return func() (string, float64, error) { return generator.GenerateToken(tcfg) }, nil
+	case mode == ModePattern:
+		p, err := generator.ParsePattern(opts.Pattern, opts.PatternClasses)
+		if err != nil {
+			return nil, &OptionError{Option: "pattern", Err: err}
+		}
+		if err := p.Exclude(exclude); err != nil {
+			return nil, &OptionError{Option: "pattern", Err: err}
+		}
+		return func() (string, float64, error) {
+			pw, err := p.Generate(opts.Rand)
+			return pw, p.Entropy(), err
+		}, nil
+	case mode == ModePassphrase:
+		return func() (string, float64, error) { return generator.GeneratePassphrase(opts.Rand, opts.WordCount, nil) }, nil
+	case mode == ModePIN:
+		rules, err := pinRules(opts.PINAllow)
+		if err != nil {
+			return nil, &OptionError{Option: "pin_allow", Err: err}
+		}
+		pcfg := generator.PinConfig{Length: opts.Length, Rules: rules, Rand: opts.Rand}
+		if opts.Length < generator.MinPINLength || opts.Length > generator.MaxPINLength {
+			return nil, &OptionError{Option: "length", Err: fmt.Errorf("PIN length must be between %d and %d", generator.MinPINLength, generator.MaxPINLength)}
+		}
+		entropy := generator.PINEntropy(pcfg)
+		return func() (string, float64, error) {
+			pin, err := generator.GeneratePIN(pcfg)
+			return pin, entropy, err
+		}, nil
+	case mode == ModePronounceable:
+		pcfg := generator.PronounceableConfig{
+			Length:          opts.Length,
+			IncludeUpper:    opts.IncludeUpper,
+			IncludeLower:    opts.IncludeLower,
+			IncludeDigits:   opts.IncludeDigits,
+			IncludeSpecials: opts.IncludeSpecials,
+			ExcludeChars:    exclude,
+			Rand:            opts.Rand,
+		}
+		return func() (string, float64, error) {
+			pw, entropy, err := generator.GeneratePronounceable(pcfg)
+			if err != nil {
+				return "", 0, &OptionError{Option: "pronounceable", Err: err}
+			}
+			return pw, entropy, nil
+		}, nil
+	case mode == ModeCharset:
+		charset, err := generator.ParseCharset(opts.CustomCharset)
+		if err != nil {
+			return nil, &OptionError{Option: "custom_charset", Err: err}
+		}
+		charset = generator.SubtractRunes(charset, []rune(exclude))
+		if len(charset) == 0 {
+			return nil, fmt.Errorf("custom charset: %w", ErrEmptyCharset)
+		}
+		entropy := float64(opts.Length) * math.Log2(float64(len(charset)))
+		return func() (string, float64, error) {
+			pw, err := generator.GenerateFromCharset(opts.Rand, charset, opts.Length)
+			return pw, entropy, err
+		}, nil
+	}
+
+	charset := []rune(generator.PasswordCharset(generator.PasswordConfig{
+		IncludeUpper:    opts.IncludeUpper,
+		IncludeLower:    opts.IncludeLower,
+		IncludeDigits:   opts.IncludeDigits,
+		IncludeSpecials: opts.IncludeSpecials,
+		ExcludeSimilar:  opts.ExcludeSimilar,
+		ExcludeChars:    exclude,
+	}))
+	if len(charset) == 0 {
+		return nil, fmt.Errorf("%w: select at least one character class", ErrEmptyCharset)
+	}
+	var required []string
+	if opts.EnforceAll {
+		for _, class := range []struct {
+			on    bool
+			chars string
+		}{
+			{opts.IncludeUpper, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+			{opts.IncludeLower, "abcdefghijklmnopqrstuvwxyz"},
+			{opts.IncludeDigits, "0123456789"},
+			{opts.IncludeSpecials, "!@#$%^&*()-_=+[]{}|;:,.<>/?"},
+		} {
+			if !class.on {
+				continue
+			}
+			chars := removeExcluded(class.chars, exclude)
+			if chars == "" {
+				return nil, &OptionError{Option: "enforce_all", Err: fmt.Errorf("exclusions remove every character of a selected class (%s)", class.chars)}
+			}
+			required = append(required, chars)
+		}
+		if len(required) > opts.Length {
+			return nil, &OptionError{Option: "enforce_all", Err: fmt.Errorf("length %d is too short for %d character classes", opts.Length, len(required))}
+		}
+	}
+	entropy := enforcedEntropy(charset, required, opts.Length)
+	return func() (string, float64, error) {
+		for {
+			if err := ctx.Err(); err != nil {
+				return "", 0, err
+			}
+			pw, err := generator.GenerateFromCharset(opts.Rand, charset, opts.Length)
+			if err != nil {
+				return "", 0, err
+			}
+			if hasAll(pw, required) {
+				return pw, entropy, nil
+			}
+		}
+	}, nil
+}
+
+// enforcedEntropy returns the entropy of length runes drawn uniformly from
+// charset and kept only when they contain a character of every required
+// set. The accepted strings are counted by inclusion-exclusion over the
+// sets: all strings, minus those missing one set, plus those missing two,
+// and so on. The sets must be disjoint, as the character classes are.
+func enforcedEntropy(charset []rune, required []string, length int) float64 {
+	sizes := make([]int, len(required))
+	for i, set := range required {
+		for _, r := range charset {
+			if strings.ContainsRune(set, r) {
+				sizes[i]++
+			}
+		}
+	}
+	accepted := new(big.Int)
+	for subset := 0; subset < 1<<len(sizes); subset++ {
+		n, odd := len(charset), false
+		for i, size := range sizes {
+			if subset&(1<<i) != 0 {
+				n -= size
+				odd = !odd
+			}
+		}
+		term := new(big.Int).Exp(big.NewInt(int64(n)), big.NewInt(int64(length)), nil)
+		if odd {
+			accepted.Sub(accepted, term)
+		} else {
+			accepted.Add(accepted, term)
+		}
+	}
+	if accepted.Sign() <= 0 {
+		return 0
+	}
+	mant := new(big.Float).SetInt(accepted)
+	exp := mant.MantExp(mant)
+	m, _ := mant.Float64()
+	return float64(exp) + math.Log2(m)
+}
+
+// hasAll reports whether pw has a character from every set.
+func hasAll(pw string, sets []string) bool {
+	for _, chars := range sets {
+		if !strings.ContainsAny(pw, chars) {
+			return false
+		}
+	}
+	return true
+}
+
+// policySource returns a source of passwords that satisfy opts.Policy. It
+// first adapts the options (length bounds, required classes) and then
+// draws candidates until one passes every rule. Candidates that only lack
+// entropy make character-based modes grow the length by one for the rest
+// of the batch. Breach lookups happen only for candidates that pass the
+// offline rules.
+func policySource(ctx context.Context, opts Options) (source, error) {
+	// Prepare caches word lists in the policy, so work on a copy that
+	// callers sharing opts.Policy never see.
+	p := *opts.Policy
+	if err := p.Prepare(); err != nil {
+		return nil, &OptionError{Option: "policy", Err: err}
+	}
+	opts.Policy = nil
+
+	charBased := (opts.Type == "" || opts.Type == "password") && !opts.Passphrase && !opts.PIN && opts.Pattern == ""
+	if charBased || opts.PIN {
+		opts.Length = max(opts.Length, p.MinLength)
+		if p.MaxLength > 0 {
+			opts.Length = min(opts.Length, p.MaxLength)
+		}
+	}
+	if opts.PIN && (p.RequireUpper || p.RequireLower || p.RequireSpecials || p.MinClasses > 1) {
+		return nil, &OptionError{Option: "policy", Err: errors.New("PINs contain only digits and cannot satisfy the policy's character class rules")}
+	}
+	if charBased && opts.CustomCharset == "" {
+		if p.RequireLetters && !opts.IncludeUpper && !opts.IncludeLower {
+			opts.IncludeLower = true
+		}
+		for _, class := range []struct {
+			required bool
+			include  *bool
+		}{
+			{p.RequireUpper, &opts.IncludeUpper},
+			{p.RequireLower, &opts.IncludeLower},
+			{p.RequireDigits, &opts.IncludeDigits},
+			{p.RequireSpecials, &opts.IncludeSpecials},
+		} {
+			if class.required {
+				*class.include = true
+				opts.EnforceAll = true
+			}
+		}
+	}
+	offline := p
+	offline.NotPwned = false
+
+	next, err := modeSource(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() (string, float64, error) {
+		var failed []string
+		for range maxPolicyAttempts {
+			if err := ctx.Err(); err != nil {
+				return "", 0, err
+			}
+			value, entropy, err := next()
+			if err != nil {
+				return "", 0, err
+			}
+			results, _ := offline.Check(value, policy.Options{Username: opts.Username, Entropy: entropy})
+			if !policy.Passed(results) {
+				failed = policy.Failed(results)
+				if charBased && len(failed) == 1 && failed[0] == "min_entropy" && (p.MaxLength == 0 || opts.Length < p.MaxLength) {
+					opts.Length++
+					if next, err = modeSource(ctx, opts); err != nil {
+						return "", 0, err
+					}
+				}
+				continue
+			}
+			if p.NotPwned {
+				pwned, _, err := CheckPwned(ctx, value)
+				if err != nil {
+					return "", 0, err
+				}
+				if pwned {
+					failed = []string{"not_pwned"}
+					continue
+				}
+			}
+			return value, entropy, nil
+		}
+		return "", 0, &PolicyError{Attempts: maxPolicyAttempts, Failing: failed}
+	}, nil
+}
+
+// exclusionSet returns every character opts asks to exclude: the expanded
+// ExcludeChars spec, the selected exclusion profiles and the characters
+// unsafe for the Target context.
+func exclusionSet(opts Options) (string, error) {
+	extra, err := generator.ParseCharset(opts.ExcludeChars)
+	if err != nil {
+		return "", &OptionError{Option: "exclude_chars", Err: err}
+	}
+	profiles := opts.ExcludeProfiles
+	if opts.ExcludeSimilar {
+		profiles = append([]string{"similar"}, profiles...)
+	}
+	chars, err := generator.ExclusionChars(profiles)
+	if err != nil {
+		return "", &OptionError{Option: "exclude_profiles", Err: err}
+	}
+	t, err := generator.LookupTarget(opts.Target)
+	if err != nil {
+		return "", &OptionError{Option: "target", Err: err}
+	}
+	return string(extra) + chars + t.Exclude, nil
+}
+
+// removeExcluded drops the characters of exclude from set.
+func removeExcluded(set, exclude string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(exclude, r) {
+			return -1
+		}
+		return r
+	}, set)
+}
+
+// pinRules turns the PINAllow list into the set of rules to enforce.
+func pinRules(allow []string) (generator.PinRules, error) {
+	rules := generator.AllPinRules
+	for _, a := range allow {
+		switch strings.ToLower(strings.TrimSpace(a)) {
+		case "sequences":
+			rules.Sequences = false
+		case "repeats":
+			rules.Repeats = false
+		case "dates":
+			rules.Dates = false
+		case "common":
+			rules.Blocklist = false
+		case "":
+		default:
+			return rules, fmt.Errorf("unknown PIN rule %q (want sequences, repeats, dates or common)", a)
+		}
+	}
+	return rules, nil
+}
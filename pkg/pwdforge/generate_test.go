@@ -0,0 +1,72 @@
+package pwdforge
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// countAccepted counts the strings of length runes from charset that have
+// a character of every required set, by trying them all.
+func countAccepted(charset []rune, required []string, length int) int {
+	idx := make([]int, length)
+	count := 0
+	for {
+		pw := make([]rune, length)
+		for i, n := range idx {
+			pw[i] = charset[n]
+		}
+		if hasAll(string(pw), required) {
+			count++
+		}
+		i := 0
+		for ; i < length; i++ {
+			if idx[i]++; idx[i] < len(charset) {
+				break
+			}
+			idx[i] = 0
+		}
+		if i == length {
+			return count
+		}
+	}
+}
+
+func TestEnforcedEntropy(t *testing.T) {
+	tests := []struct {
+		charset  string
+		required []string
+		length   int
+	}{
+		{"ABab01!?", []string{"AB", "ab", "01", "!?"}, 4},
+		{"ABab01!?", []string{"AB", "ab", "01", "!?"}, 6},
+		{"ABCab0!", []string{"ABC", "ab", "0", "!"}, 5},
+		{"ABCabc", []string{"ABC", "abc"}, 3},
+		// Required characters missing from the charset count as absent.
+		{"ABab", []string{"ABZ", "abz"}, 4},
+		{"abc", nil, 5},
+	}
+	for _, tt := range tests {
+		charset := []rune(tt.charset)
+		want := math.Log2(float64(countAccepted(charset, tt.required, tt.length)))
+		if got := enforcedEntropy(charset, tt.required, tt.length); math.Abs(got-want) > 1e-9 {
+			t.Errorf("enforcedEntropy(%q, %q, %d) = %v, want %v", tt.charset, tt.required, tt.length, got, want)
+		}
+	}
+}
+
+func TestEnforceAllEntropy(t *testing.T) {
+	opts := DefaultOptions()
+	opts.EnforceAll = true
+	opts.Length = 4
+	secrets, err := Generate(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without enforce_all, 4 characters from 89 would be 25.9 bits; only
+	// 26*26*10*27 orderings of one character per class, times 4!, pass.
+	want := math.Log2(26 * 26 * 10 * 27 * 24)
+	if got := secrets[0].Entropy; math.Abs(got-want) > 1e-9 {
+		t.Errorf("entropy of a 4-character enforce_all password = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,193 @@
+package pwdforge
+
+import (
+	"io"
+
+	"pwdforge/internal/generator"
+	"pwdforge/internal/policy"
+)
+
+// Defaults used for zero-valued Options fields.
+const (
+	DefaultLength     = 12
+	DefaultPINLength  = 6
+	DefaultWordCount  = 4
+	DefaultTokenBytes = 32
+	DefaultKeyPrefix  = generator.DefaultAPIKeyPrefix
+)
+
+type (
+	// Policy is an organisation password policy, the "policy" section of a
+	// config file.
+	Policy = policy.Policy
+	// PolicyResult is the outcome of one policy rule.
+	PolicyResult = policy.Result
+	// Blocklist is a set of banned words matched case-insensitively and
+	// through l33t substitutions.
+	Blocklist = generator.Blocklist
+)
+
+// Options selects what Generate produces. The yaml and json keys are those
+// of pwdforge config files. Exactly one mode applies, checked in this
+// order: Type (other than "password"), Pattern, Passphrase, PIN,
+// Pronounceable, CustomCharset, and otherwise random characters from the
+// Include* classes.
+type Options struct {
+	// Length is in characters (digits for PINs). Zero means DefaultLength,
+	// or DefaultPINLength for PINs.
+	Length int `yaml:"length" json:"length"`
+	// Count is the number of secrets; zero means one.
+	Count           int  `yaml:"count" json:"count"`
+	IncludeUpper    bool `yaml:"include_upper" json:"include_upper"`
+	IncludeLower    bool `yaml:"include_lower" json:"include_lower"`
+	IncludeDigits   bool `yaml:"include_digits" json:"include_digits"`
+	IncludeSpecials bool `yaml:"include_specials" json:"include_specials"`
+	// ExcludeSimilar drops look-alike characters such as l, 1, O and 0.
+	ExcludeSimilar bool `yaml:"exclude_similar" json:"exclude_similar"`
+	// ExcludeProfiles names further sets to drop; see ExclusionProfileNames.
+	ExcludeProfiles []string `yaml:"exclude_profiles" json:"exclude_profiles"`
+	// CustomCharset replaces the classes, e.g. "a-z0-9" or `\p{Greek}`.
+	CustomCharset string `yaml:"custom_charset" json:"custom_charset"`
+	// ExcludeChars removes characters, in CustomCharset syntax.
+	ExcludeChars string `yaml:"exclude_chars" json:"exclude_chars"`
+	// EnforceAll requires at least one character of every selected class.
+	EnforceAll    bool `yaml:"enforce_all" json:"enforce_all"`
+	Passphrase    bool `yaml:"passphrase" json:"passphrase"`
+	Pronounceable bool `yaml:"pronounceable" json:"pronounceable"`
+	PIN           bool `yaml:"pin" json:"pin"`
+	// PINAllow permits weak PIN patterns: sequences, repeats, dates, common.
+	PINAllow []string `yaml:"pin_allow" json:"pin_allow"`
+	// Pattern is a template such as "LLLL-DDDD-SSSS" (see the README).
+	Pattern string `yaml:"pattern" json:"pattern"`
+	// Target makes secrets safe for a context such as json or shell; see
+	// TargetNames. Render escapes a secret for it.
+	Target string `yaml:"target" json:"target"`
+	// Type selects a machine secret instead of a password; see TokenTypes.
+	Type string `yaml:"type" json:"type"`
+	// Bytes is the randomness of token types; zero means DefaultTokenBytes.
+	Bytes     int    `yaml:"bytes" json:"bytes"`
+	KeyPrefix string `yaml:"key_prefix" json:"key_prefix"`
+	// PatternClasses names extra character classes usable as [name] in patterns.
+	PatternClasses map[string]string `yaml:"pattern_classes" json:"pattern_classes"`
+	// WordCount is the passphrase length; zero means DefaultWordCount.
+	WordCount int `yaml:"word_count" json:"word_count"`
+	// Policy, when set, is enforced on every generated password.
+	Policy *Policy `yaml:"policy" json:"policy"`
+	// Context values (user, service, ...) may not appear in passwords.
+	Context map[string]string `yaml:"context" json:"context"`
+	// Username is the account the passwords are for, checked by the
+	// policy's disallow_username rule.
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+
+	// Rand is the source of randomness and must be cryptographically
+	// secure; nil means crypto/rand. A buffered reader per goroutine, such
+	// as bufio.NewReader(rand.Reader), avoids a system call per secret
+	// when generating in parallel. It must not be shared between
+	// concurrent calls.
+	Rand io.Reader `yaml:"-" json:"-"`
+}
+
+// Generation modes reported by Options.Mode. Token types (TokenTypes) are
+// modes of their own.
+const (
+	ModePassword      = "password"
+	ModePattern       = "pattern"
+	ModePassphrase    = "passphrase"
+	ModePIN           = "pin"
+	ModePronounceable = "pronounceable"
+	ModeCharset       = "charset"
+)
+
+// Mode names what Generate produces for o: the token Type when it is not
+// "password", otherwise the first of Pattern, Passphrase, PIN,
+// Pronounceable and CustomCharset that is set, or ModePassword.
+func (o Options) Mode() string {
+	switch {
+	case o.Type != "" && o.Type != ModePassword:
+		return o.Type
+	case o.Pattern != "":
+		return ModePattern
+	case o.Passphrase:
+		return ModePassphrase
+	case o.PIN:
+		return ModePIN
+	case o.Pronounceable:
+		return ModePronounceable
+	case o.CustomCharset != "":
+		return ModeCharset
+	}
+	return ModePassword
+}
+
+// DefaultOptions returns the defaults of "pwdforge generate": one
+// 12-character password from all four character classes.
+func DefaultOptions() Options {
+	return Options{
+		Length:          DefaultLength,
+		Count:           1,
+		IncludeUpper:    true,
+		IncludeLower:    true,
+		IncludeDigits:   true,
+		IncludeSpecials: true,
+		Bytes:           DefaultTokenBytes,
+		KeyPrefix:       DefaultKeyPrefix,
+		WordCount:       DefaultWordCount,
+	}
+}
+
+// TokenTypes lists the machine secret types for Options.Type.
+func TokenTypes() []string { return append([]string(nil), generator.TokenTypes...) }
+
+// ValidateAPIKey checks the structure and checksum of a key generated with
+// Type "apikey" and returns its prefix.
+func ValidateAPIKey(key string) (string, error) { return generator.ValidateAPIKey(key) }
+
+// TargetNames lists the contexts for Options.Target.
+func TargetNames() []string { return generator.TargetNames() }
+
+// ExclusionProfileNames lists the profiles for Options.ExcludeProfiles.
+func ExclusionProfileNames() []string { return generator.ExclusionProfileNames() }
+
+// Render returns secret as it should be written for target, e.g. quoted
+// for json. Targets that only restrict characters return it unchanged.
+func Render(target, secret string) (string, error) {
+	t, err := generator.LookupTarget(target)
+	if err != nil {
+		return "", &OptionError{Option: "target", Err: err}
+	}
+	return t.Render(secret), nil
+}
+
+// PatternLength returns the number of characters passwords from pattern
+// have, with classes as in Options.PatternClasses, or an *OptionError if
+// the pattern does not parse.
+func PatternLength(pattern string, classes map[string]string) (int, error) {
+	p, err := generator.ParsePattern(pattern, classes)
+	if err != nil {
+		return 0, &OptionError{Option: "pattern", Err: err}
+	}
+	return p.Len(), nil
+}
+
+// CharsetSize returns how many characters a charset spec, as used by
+// CustomCharset, ExcludeChars and PatternClasses, expands to without
+// expanding it. Overlapping ranges and classes count once per occurrence,
+// so it is an upper bound; servers use it to cap the work a request can
+// cause.
+func CharsetSize(spec string) (int, error) { return generator.CharsetSize(spec) }
+
+// NewBlocklist returns a blocklist holding words.
+func NewBlocklist(words ...string) *Blocklist { return generator.NewBlocklist(words...) }
+
+// MergeBlocklists returns a blocklist holding the words of a and b, either
+// of which may be nil.
+func MergeBlocklists(a, b *Blocklist) *Blocklist { return generator.MergeBlocklists(a, b) }
+
+// LoadBlocklist reads a word list file, one word per line.
+func LoadBlocklist(path string) (*Blocklist, error) {
+	b := generator.NewBlocklist()
+	if err := b.LoadFile(path); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
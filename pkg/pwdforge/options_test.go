@@ -0,0 +1,38 @@
+package pwdforge
+
+import (
+	"errors"
+	"testing"
+
+	"pwdforge/internal/generator"
+)
+
+func TestPatternLength(t *testing.T) {
+	classes := map[string]string{"hex": "0-9a-f"}
+	tests := []struct {
+		pattern string
+		want    int
+	}{
+		{"Cvccvc-DDDD", 11},
+		{"[hex]{32}", 32},
+		{`L\{2\}`, 4},
+		{"ünï{3}", 5},
+	}
+	for _, tt := range tests {
+		if got, err := PatternLength(tt.pattern, classes); got != tt.want || err != nil {
+			t.Errorf("PatternLength(%q) = %d, %v, want %d", tt.pattern, got, err, tt.want)
+		}
+	}
+}
+
+func TestPatternLengthError(t *testing.T) {
+	_, err := PatternLength("LLLL[oct]", nil)
+	var oerr *OptionError
+	if !errors.As(err, &oerr) || oerr.Option != "pattern" {
+		t.Fatalf("PatternLength error = %v, want an *OptionError for pattern", err)
+	}
+	var perr *generator.PatternError
+	if !errors.As(err, &perr) || perr.Pos != 6 || perr.Msg != `unknown class "oct"` {
+		t.Errorf("PatternLength error = %#v, want unknown class at position 6", perr)
+	}
+}
@@ -0,0 +1,158 @@
+// Package pwdforgeclient connects to a "pwdforge serve-grpc" server.
+//
+//	c, err := pwdforgeclient.Dial("pwdforge.internal:9090", pwdforgeclient.Options{
+//		Token:  os.Getenv("PWDFORGE_API_TOKEN"),
+//		CAFile: "ca.pem",
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close()
+//	resp, err := c.Generate(ctx, &pwdforgepb.GenerateRequest{Length: 24, Count: 3})
+package pwdforgeclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"pwdforge/pkg/pwdforgepb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type Options struct {
+	// Token is sent as "authorization: Bearer <token>" on every call.
+	Token string
+	// CAFile verifies the server certificate; empty uses the system roots.
+	CAFile string
+	// CertFile and KeyFile present a client certificate (mTLS).
+	CertFile, KeyFile string
+	// Insecure connects without TLS, e.g. to a server on localhost.
+	Insecure bool
+}
+
+// Client is a PwdForgeClient that owns its connection.
+type Client struct {
+	pwdforgepb.PwdForgeClient
+	conn *grpc.ClientConn
+}
+
+// Dial prepares a connection to target; it connects on the first call.
+func Dial(target string, opts Options) (*Client, error) {
+	var dialOpts []grpc.DialOption
+	if opts.Insecure {
+		if opts.CAFile != "" || opts.CertFile != "" {
+			return nil, errors.New("TLS files given for an insecure connection")
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+		if opts.CAFile != "" {
+			pem, err := os.ReadFile(opts.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			cfg.RootCAs = x509.NewCertPool()
+			if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in %s", opts.CAFile)
+			}
+		}
+		if (opts.CertFile == "") != (opts.KeyFile == "") {
+			return nil, errors.New("a client certificate needs both CertFile and KeyFile")
+		}
+		if opts.CertFile != "" {
+			cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+			if err != nil {
+				return nil, err
+			}
+			cfg.Certificates = []tls.Certificate{cert}
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(cfg)))
+	}
+	if opts.Token != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(bearerToken{token: opts.Token, insecure: opts.Insecure}))
+	}
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{PwdForgeClient: pwdforgepb.NewPwdForgeClient(conn), conn: conn}, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// CheckStrengthAll checks every request over one CheckStrengthBatch stream
+// and returns the results in request order.
+func (c *Client) CheckStrengthAll(ctx context.Context, reqs []*pwdforgepb.CheckStrengthRequest) ([]*pwdforgepb.CheckStrengthResult, error) {
+	stream, err := c.CheckStrengthBatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return batch(stream, reqs)
+}
+
+// CheckPwnedAll is CheckStrengthAll for breach checks.
+func (c *Client) CheckPwnedAll(ctx context.Context, reqs []*pwdforgepb.CheckPwnedRequest) ([]*pwdforgepb.CheckPwnedResult, error) {
+	stream, err := c.CheckPwnedBatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return batch(stream, reqs)
+}
+
+// batch sends reqs while receiving results, so neither side waits for the
+// whole batch.
+func batch[Req, Res any](stream grpc.BidiStreamingClient[Req, Res], reqs []*Req) ([]*Res, error) {
+	sendErr := make(chan error, 1)
+	go func() {
+		for _, req := range reqs {
+			if err := stream.Send(req); err != nil {
+				// The receive side reports the stream's status.
+				sendErr <- nil
+				return
+			}
+		}
+		sendErr <- stream.CloseSend()
+	}()
+	results := make([]*Res, 0, len(reqs))
+	for {
+		res, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	if err := <-sendErr; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// bearerToken attaches the API token to each call.
+type bearerToken struct {
+	token    string
+	insecure bool
+}
+
+func (b bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + b.token}, nil
+}
+
+// RequireTransportSecurity keeps tokens off plaintext connections unless
+// Insecure was chosen explicitly.
+func (b bearerToken) RequireTransportSecurity() bool {
+	return !b.insecure
+}
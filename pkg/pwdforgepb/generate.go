@@ -0,0 +1,6 @@
+// Package pwdforgepb holds the Go code generated from
+// proto/pwdforge/v1/pwdforge.proto. Use pwdforge/pkg/pwdforgeclient to
+// connect to a server.
+package pwdforgepb
+
+//go:generate protoc -I ../../proto --go_out=. --go_opt=module=pwdforge/pkg/pwdforgepb --go-grpc_out=. --go-grpc_opt=module=pwdforge/pkg/pwdforgepb pwdforge/v1/pwdforge.proto
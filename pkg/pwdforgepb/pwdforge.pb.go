@@ -0,0 +1,1254 @@
+// gRPC interface of PwdForge. It mirrors the JSON HTTP API served by
+// "pwdforge serve": field names match the config file keys.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: pwdforge/v1/pwdforge.proto
+
+package pwdforgepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Policy is the policy section of a config file. Word list files are not
+// accepted over the network; send banned_words instead.
+type Policy struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	MinLength        int32                  `protobuf:"varint,1,opt,name=min_length,json=minLength,proto3" json:"min_length,omitempty"`
+	MaxLength        int32                  `protobuf:"varint,2,opt,name=max_length,json=maxLength,proto3" json:"max_length,omitempty"`
+	RequireUpper     bool                   `protobuf:"varint,3,opt,name=require_upper,json=requireUpper,proto3" json:"require_upper,omitempty"`
+	RequireLower     bool                   `protobuf:"varint,4,opt,name=require_lower,json=requireLower,proto3" json:"require_lower,omitempty"`
+	RequireDigits    bool                   `protobuf:"varint,5,opt,name=require_digits,json=requireDigits,proto3" json:"require_digits,omitempty"`
+	RequireSpecials  bool                   `protobuf:"varint,6,opt,name=require_specials,json=requireSpecials,proto3" json:"require_specials,omitempty"`
+	RequireLetters   bool                   `protobuf:"varint,7,opt,name=require_letters,json=requireLetters,proto3" json:"require_letters,omitempty"`
+	MinClasses       int32                  `protobuf:"varint,8,opt,name=min_classes,json=minClasses,proto3" json:"min_classes,omitempty"`
+	MaxRepeated      int32                  `protobuf:"varint,9,opt,name=max_repeated,json=maxRepeated,proto3" json:"max_repeated,omitempty"`
+	BannedWords      []string               `protobuf:"bytes,10,rep,name=banned_words,json=bannedWords,proto3" json:"banned_words,omitempty"`
+	DisallowUsername bool                   `protobuf:"varint,11,opt,name=disallow_username,json=disallowUsername,proto3" json:"disallow_username,omitempty"`
+	CompanyNames     []string               `protobuf:"bytes,12,rep,name=company_names,json=companyNames,proto3" json:"company_names,omitempty"`
+	MinEntropy       float64                `protobuf:"fixed64,13,opt,name=min_entropy,json=minEntropy,proto3" json:"min_entropy,omitempty"`
+	NotPwned         bool                   `protobuf:"varint,14,opt,name=not_pwned,json=notPwned,proto3" json:"not_pwned,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Policy) Reset() {
+	*x = Policy{}
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Policy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy) ProtoMessage() {}
+
+func (x *Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
+func (*Policy) Descriptor() ([]byte, []int) {
+	return file_pwdforge_v1_pwdforge_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Policy) GetMinLength() int32 {
+	if x != nil {
+		return x.MinLength
+	}
+	return 0
+}
+
+func (x *Policy) GetMaxLength() int32 {
+	if x != nil {
+		return x.MaxLength
+	}
+	return 0
+}
+
+func (x *Policy) GetRequireUpper() bool {
+	if x != nil {
+		return x.RequireUpper
+	}
+	return false
+}
+
+func (x *Policy) GetRequireLower() bool {
+	if x != nil {
+		return x.RequireLower
+	}
+	return false
+}
+
+func (x *Policy) GetRequireDigits() bool {
+	if x != nil {
+		return x.RequireDigits
+	}
+	return false
+}
+
+func (x *Policy) GetRequireSpecials() bool {
+	if x != nil {
+		return x.RequireSpecials
+	}
+	return false
+}
+
+func (x *Policy) GetRequireLetters() bool {
+	if x != nil {
+		return x.RequireLetters
+	}
+	return false
+}
+
+func (x *Policy) GetMinClasses() int32 {
+	if x != nil {
+		return x.MinClasses
+	}
+	return 0
+}
+
+func (x *Policy) GetMaxRepeated() int32 {
+	if x != nil {
+		return x.MaxRepeated
+	}
+	return 0
+}
+
+func (x *Policy) GetBannedWords() []string {
+	if x != nil {
+		return x.BannedWords
+	}
+	return nil
+}
+
+func (x *Policy) GetDisallowUsername() bool {
+	if x != nil {
+		return x.DisallowUsername
+	}
+	return false
+}
+
+func (x *Policy) GetCompanyNames() []string {
+	if x != nil {
+		return x.CompanyNames
+	}
+	return nil
+}
+
+func (x *Policy) GetMinEntropy() float64 {
+	if x != nil {
+		return x.MinEntropy
+	}
+	return 0
+}
+
+func (x *Policy) GetNotPwned() bool {
+	if x != nil {
+		return x.NotPwned
+	}
+	return false
+}
+
+// GenerateRequest takes the generate options. Unset fields get the CLI
+// defaults: 12 characters from all classes, 32 token bytes, 4 words.
+type GenerateRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Length          int32                  `protobuf:"varint,1,opt,name=length,proto3" json:"length,omitempty"`
+	Count           int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	IncludeUpper    bool                   `protobuf:"varint,3,opt,name=include_upper,json=includeUpper,proto3" json:"include_upper,omitempty"`
+	IncludeLower    bool                   `protobuf:"varint,4,opt,name=include_lower,json=includeLower,proto3" json:"include_lower,omitempty"`
+	IncludeDigits   bool                   `protobuf:"varint,5,opt,name=include_digits,json=includeDigits,proto3" json:"include_digits,omitempty"`
+	IncludeSpecials bool                   `protobuf:"varint,6,opt,name=include_specials,json=includeSpecials,proto3" json:"include_specials,omitempty"`
+	ExcludeSimilar  bool                   `protobuf:"varint,7,opt,name=exclude_similar,json=excludeSimilar,proto3" json:"exclude_similar,omitempty"`
+	ExcludeProfiles []string               `protobuf:"bytes,8,rep,name=exclude_profiles,json=excludeProfiles,proto3" json:"exclude_profiles,omitempty"`
+	CustomCharset   string                 `protobuf:"bytes,9,opt,name=custom_charset,json=customCharset,proto3" json:"custom_charset,omitempty"`
+	ExcludeChars    string                 `protobuf:"bytes,10,opt,name=exclude_chars,json=excludeChars,proto3" json:"exclude_chars,omitempty"`
+	EnforceAll      bool                   `protobuf:"varint,11,opt,name=enforce_all,json=enforceAll,proto3" json:"enforce_all,omitempty"`
+	Passphrase      bool                   `protobuf:"varint,12,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
+	WordCount       int32                  `protobuf:"varint,13,opt,name=word_count,json=wordCount,proto3" json:"word_count,omitempty"`
+	Pronounceable   bool                   `protobuf:"varint,14,opt,name=pronounceable,proto3" json:"pronounceable,omitempty"`
+	Pin             bool                   `protobuf:"varint,15,opt,name=pin,proto3" json:"pin,omitempty"`
+	PinAllow        []string               `protobuf:"bytes,16,rep,name=pin_allow,json=pinAllow,proto3" json:"pin_allow,omitempty"`
+	Pattern         string                 `protobuf:"bytes,17,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	PatternClasses  map[string]string      `protobuf:"bytes,18,rep,name=pattern_classes,json=patternClasses,proto3" json:"pattern_classes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// type is password (default), apikey, hex, base64, base64url, uuid or jwt-secret.
+	Type      string `protobuf:"bytes,19,opt,name=type,proto3" json:"type,omitempty"`
+	Bytes     int32  `protobuf:"varint,20,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	KeyPrefix string `protobuf:"bytes,21,opt,name=key_prefix,json=keyPrefix,proto3" json:"key_prefix,omitempty"`
+	// target renders each secret for a config format, e.g. json or env.
+	Target string `protobuf:"bytes,22,opt,name=target,proto3" json:"target,omitempty"`
+	// hash adds a hash of each password, e.g. bcrypt or argon2id.
+	Hash     string `protobuf:"bytes,23,opt,name=hash,proto3" json:"hash,omitempty"`
+	HashOnly bool   `protobuf:"varint,24,opt,name=hash_only,json=hashOnly,proto3" json:"hash_only,omitempty"`
+	// context values (user, service, ...) may not appear in passwords.
+	Context       map[string]string `protobuf:"bytes,25,rep,name=context,proto3" json:"context,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Policy        *Policy           `protobuf:"bytes,26,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateRequest) Reset() {
+	*x = GenerateRequest{}
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateRequest) ProtoMessage() {}
+
+func (x *GenerateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateRequest.ProtoReflect.Descriptor instead.
+func (*GenerateRequest) Descriptor() ([]byte, []int) {
+	return file_pwdforge_v1_pwdforge_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GenerateRequest) GetLength() int32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *GenerateRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *GenerateRequest) GetIncludeUpper() bool {
+	if x != nil {
+		return x.IncludeUpper
+	}
+	return false
+}
+
+func (x *GenerateRequest) GetIncludeLower() bool {
+	if x != nil {
+		return x.IncludeLower
+	}
+	return false
+}
+
+func (x *GenerateRequest) GetIncludeDigits() bool {
+	if x != nil {
+		return x.IncludeDigits
+	}
+	return false
+}
+
+func (x *GenerateRequest) GetIncludeSpecials() bool {
+	if x != nil {
+		return x.IncludeSpecials
+	}
+	return false
+}
+
+func (x *GenerateRequest) GetExcludeSimilar() bool {
+	if x != nil {
+		return x.ExcludeSimilar
+	}
+	return false
+}
+
+func (x *GenerateRequest) GetExcludeProfiles() []string {
+	if x != nil {
+		return x.ExcludeProfiles
+	}
+	return nil
+}
+
+func (x *GenerateRequest) GetCustomCharset() string {
+	if x != nil {
+		return x.CustomCharset
+	}
+	return ""
+}
+
+func (x *GenerateRequest) GetExcludeChars() string {
+	if x != nil {
+		return x.ExcludeChars
+	}
+	return ""
+}
+
+func (x *GenerateRequest) GetEnforceAll() bool {
+	if x != nil {
+		return x.EnforceAll
+	}
+	return false
+}
+
+func (x *GenerateRequest) GetPassphrase() bool {
+	if x != nil {
+		return x.Passphrase
+	}
+	return false
+}
+
+func (x *GenerateRequest) GetWordCount() int32 {
+	if x != nil {
+		return x.WordCount
+	}
+	return 0
+}
+
+func (x *GenerateRequest) GetPronounceable() bool {
+	if x != nil {
+		return x.Pronounceable
+	}
+	return false
+}
+
+func (x *GenerateRequest) GetPin() bool {
+	if x != nil {
+		return x.Pin
+	}
+	return false
+}
+
+func (x *GenerateRequest) GetPinAllow() []string {
+	if x != nil {
+		return x.PinAllow
+	}
+	return nil
+}
+
+func (x *GenerateRequest) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *GenerateRequest) GetPatternClasses() map[string]string {
+	if x != nil {
+		return x.PatternClasses
+	}
+	return nil
+}
+
+func (x *GenerateRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *GenerateRequest) GetBytes() int32 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *GenerateRequest) GetKeyPrefix() string {
+	if x != nil {
+		return x.KeyPrefix
+	}
+	return ""
+}
+
+func (x *GenerateRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *GenerateRequest) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *GenerateRequest) GetHashOnly() bool {
+	if x != nil {
+		return x.HashOnly
+	}
+	return false
+}
+
+func (x *GenerateRequest) GetContext() map[string]string {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+func (x *GenerateRequest) GetPolicy() *Policy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type GeneratedSecret struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Password      string                 `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+	Rendered      string                 `protobuf:"bytes,2,opt,name=rendered,proto3" json:"rendered,omitempty"`
+	Hash          string                 `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	Strength      string                 `protobuf:"bytes,4,opt,name=strength,proto3" json:"strength,omitempty"`
+	Entropy       float64                `protobuf:"fixed64,5,opt,name=entropy,proto3" json:"entropy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeneratedSecret) Reset() {
+	*x = GeneratedSecret{}
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeneratedSecret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratedSecret) ProtoMessage() {}
+
+func (x *GeneratedSecret) ProtoReflect() protoreflect.Message {
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratedSecret.ProtoReflect.Descriptor instead.
+func (*GeneratedSecret) Descriptor() ([]byte, []int) {
+	return file_pwdforge_v1_pwdforge_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GeneratedSecret) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *GeneratedSecret) GetRendered() string {
+	if x != nil {
+		return x.Rendered
+	}
+	return ""
+}
+
+func (x *GeneratedSecret) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *GeneratedSecret) GetStrength() string {
+	if x != nil {
+		return x.Strength
+	}
+	return ""
+}
+
+func (x *GeneratedSecret) GetEntropy() float64 {
+	if x != nil {
+		return x.Entropy
+	}
+	return 0
+}
+
+type GenerateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Passwords     []*GeneratedSecret     `protobuf:"bytes,1,rep,name=passwords,proto3" json:"passwords,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateResponse) Reset() {
+	*x = GenerateResponse{}
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateResponse) ProtoMessage() {}
+
+func (x *GenerateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateResponse.ProtoReflect.Descriptor instead.
+func (*GenerateResponse) Descriptor() ([]byte, []int) {
+	return file_pwdforge_v1_pwdforge_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GenerateResponse) GetPasswords() []*GeneratedSecret {
+	if x != nil {
+		return x.Passwords
+	}
+	return nil
+}
+
+type CheckStrengthRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Password    string                 `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+	BannedWords []string               `protobuf:"bytes,2,rep,name=banned_words,json=bannedWords,proto3" json:"banned_words,omitempty"`
+	// context values may not appear in the password; "user" is also the
+	// username for the policy's disallow_username rule.
+	Context       map[string]string `protobuf:"bytes,3,rep,name=context,proto3" json:"context,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Policy        *Policy           `protobuf:"bytes,4,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckStrengthRequest) Reset() {
+	*x = CheckStrengthRequest{}
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckStrengthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckStrengthRequest) ProtoMessage() {}
+
+func (x *CheckStrengthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckStrengthRequest.ProtoReflect.Descriptor instead.
+func (*CheckStrengthRequest) Descriptor() ([]byte, []int) {
+	return file_pwdforge_v1_pwdforge_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CheckStrengthRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CheckStrengthRequest) GetBannedWords() []string {
+	if x != nil {
+		return x.BannedWords
+	}
+	return nil
+}
+
+func (x *CheckStrengthRequest) GetContext() map[string]string {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+func (x *CheckStrengthRequest) GetPolicy() *Policy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type PolicyRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          string                 `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Passed        bool                   `protobuf:"varint,2,opt,name=passed,proto3" json:"passed,omitempty"`
+	Detail        string                 `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyRule) Reset() {
+	*x = PolicyRule{}
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyRule) ProtoMessage() {}
+
+func (x *PolicyRule) ProtoReflect() protoreflect.Message {
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyRule.ProtoReflect.Descriptor instead.
+func (*PolicyRule) Descriptor() ([]byte, []int) {
+	return file_pwdforge_v1_pwdforge_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PolicyRule) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *PolicyRule) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+func (x *PolicyRule) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+type PolicyReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Passed        bool                   `protobuf:"varint,1,opt,name=passed,proto3" json:"passed,omitempty"`
+	Rules         []*PolicyRule          `protobuf:"bytes,2,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PolicyReport) Reset() {
+	*x = PolicyReport{}
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PolicyReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicyReport) ProtoMessage() {}
+
+func (x *PolicyReport) ProtoReflect() protoreflect.Message {
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicyReport.ProtoReflect.Descriptor instead.
+func (*PolicyReport) Descriptor() ([]byte, []int) {
+	return file_pwdforge_v1_pwdforge_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PolicyReport) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+func (x *PolicyReport) GetRules() []*PolicyRule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type CheckStrengthResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Strength    string                 `protobuf:"bytes,1,opt,name=strength,proto3" json:"strength,omitempty"`
+	Entropy     float64                `protobuf:"fixed64,2,opt,name=entropy,proto3" json:"entropy,omitempty"`
+	Suggestions []string               `protobuf:"bytes,3,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	// policy is set when the request had a policy.
+	Policy        *PolicyReport `protobuf:"bytes,4,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckStrengthResponse) Reset() {
+	*x = CheckStrengthResponse{}
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckStrengthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckStrengthResponse) ProtoMessage() {}
+
+func (x *CheckStrengthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckStrengthResponse.ProtoReflect.Descriptor instead.
+func (*CheckStrengthResponse) Descriptor() ([]byte, []int) {
+	return file_pwdforge_v1_pwdforge_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CheckStrengthResponse) GetStrength() string {
+	if x != nil {
+		return x.Strength
+	}
+	return ""
+}
+
+func (x *CheckStrengthResponse) GetEntropy() float64 {
+	if x != nil {
+		return x.Entropy
+	}
+	return 0
+}
+
+func (x *CheckStrengthResponse) GetSuggestions() []string {
+	if x != nil {
+		return x.Suggestions
+	}
+	return nil
+}
+
+func (x *CheckStrengthResponse) GetPolicy() *PolicyReport {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type CheckStrengthResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// index counts requests on the stream from 0.
+	Index         uint64                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Response      *CheckStrengthResponse `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckStrengthResult) Reset() {
+	*x = CheckStrengthResult{}
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckStrengthResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckStrengthResult) ProtoMessage() {}
+
+func (x *CheckStrengthResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckStrengthResult.ProtoReflect.Descriptor instead.
+func (*CheckStrengthResult) Descriptor() ([]byte, []int) {
+	return file_pwdforge_v1_pwdforge_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CheckStrengthResult) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *CheckStrengthResult) GetResponse() *CheckStrengthResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *CheckStrengthResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type CheckPwnedRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Secret:
+	//
+	//	*CheckPwnedRequest_Password
+	//	*CheckPwnedRequest_Sha1
+	Secret        isCheckPwnedRequest_Secret `protobuf_oneof:"secret"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckPwnedRequest) Reset() {
+	*x = CheckPwnedRequest{}
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckPwnedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckPwnedRequest) ProtoMessage() {}
+
+func (x *CheckPwnedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckPwnedRequest.ProtoReflect.Descriptor instead.
+func (*CheckPwnedRequest) Descriptor() ([]byte, []int) {
+	return file_pwdforge_v1_pwdforge_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CheckPwnedRequest) GetSecret() isCheckPwnedRequest_Secret {
+	if x != nil {
+		return x.Secret
+	}
+	return nil
+}
+
+func (x *CheckPwnedRequest) GetPassword() string {
+	if x != nil {
+		if x, ok := x.Secret.(*CheckPwnedRequest_Password); ok {
+			return x.Password
+		}
+	}
+	return ""
+}
+
+func (x *CheckPwnedRequest) GetSha1() string {
+	if x != nil {
+		if x, ok := x.Secret.(*CheckPwnedRequest_Sha1); ok {
+			return x.Sha1
+		}
+	}
+	return ""
+}
+
+type isCheckPwnedRequest_Secret interface {
+	isCheckPwnedRequest_Secret()
+}
+
+type CheckPwnedRequest_Password struct {
+	Password string `protobuf:"bytes,1,opt,name=password,proto3,oneof"`
+}
+
+type CheckPwnedRequest_Sha1 struct {
+	// sha1 is the hex SHA-1 of the password, so it never leaves the client.
+	Sha1 string `protobuf:"bytes,2,opt,name=sha1,proto3,oneof"`
+}
+
+func (*CheckPwnedRequest_Password) isCheckPwnedRequest_Secret() {}
+
+func (*CheckPwnedRequest_Sha1) isCheckPwnedRequest_Secret() {}
+
+type CheckPwnedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pwned         bool                   `protobuf:"varint,1,opt,name=pwned,proto3" json:"pwned,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckPwnedResponse) Reset() {
+	*x = CheckPwnedResponse{}
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckPwnedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckPwnedResponse) ProtoMessage() {}
+
+func (x *CheckPwnedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckPwnedResponse.ProtoReflect.Descriptor instead.
+func (*CheckPwnedResponse) Descriptor() ([]byte, []int) {
+	return file_pwdforge_v1_pwdforge_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CheckPwnedResponse) GetPwned() bool {
+	if x != nil {
+		return x.Pwned
+	}
+	return false
+}
+
+func (x *CheckPwnedResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type CheckPwnedResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         uint64                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Response      *CheckPwnedResponse    `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckPwnedResult) Reset() {
+	*x = CheckPwnedResult{}
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckPwnedResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckPwnedResult) ProtoMessage() {}
+
+func (x *CheckPwnedResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pwdforge_v1_pwdforge_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckPwnedResult.ProtoReflect.Descriptor instead.
+func (*CheckPwnedResult) Descriptor() ([]byte, []int) {
+	return file_pwdforge_v1_pwdforge_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CheckPwnedResult) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *CheckPwnedResult) GetResponse() *CheckPwnedResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *CheckPwnedResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_pwdforge_v1_pwdforge_proto protoreflect.FileDescriptor
+
+const file_pwdforge_v1_pwdforge_proto_rawDesc = "" +
+	"\n" +
+	"\x1apwdforge/v1/pwdforge.proto\x12\vpwdforge.v1\"\x82\x04\n" +
+	"\x06Policy\x12\x1d\n" +
+	"\n" +
+	"min_length\x18\x01 \x01(\x05R\tminLength\x12\x1d\n" +
+	"\n" +
+	"max_length\x18\x02 \x01(\x05R\tmaxLength\x12#\n" +
+	"\rrequire_upper\x18\x03 \x01(\bR\frequireUpper\x12#\n" +
+	"\rrequire_lower\x18\x04 \x01(\bR\frequireLower\x12%\n" +
+	"\x0erequire_digits\x18\x05 \x01(\bR\rrequireDigits\x12)\n" +
+	"\x10require_specials\x18\x06 \x01(\bR\x0frequireSpecials\x12'\n" +
+	"\x0frequire_letters\x18\a \x01(\bR\x0erequireLetters\x12\x1f\n" +
+	"\vmin_classes\x18\b \x01(\x05R\n" +
+	"minClasses\x12!\n" +
+	"\fmax_repeated\x18\t \x01(\x05R\vmaxRepeated\x12!\n" +
+	"\fbanned_words\x18\n" +
+	" \x03(\tR\vbannedWords\x12+\n" +
+	"\x11disallow_username\x18\v \x01(\bR\x10disallowUsername\x12#\n" +
+	"\rcompany_names\x18\f \x03(\tR\fcompanyNames\x12\x1f\n" +
+	"\vmin_entropy\x18\r \x01(\x01R\n" +
+	"minEntropy\x12\x1b\n" +
+	"\tnot_pwned\x18\x0e \x01(\bR\bnotPwned\"\xa8\b\n" +
+	"\x0fGenerateRequest\x12\x16\n" +
+	"\x06length\x18\x01 \x01(\x05R\x06length\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12#\n" +
+	"\rinclude_upper\x18\x03 \x01(\bR\fincludeUpper\x12#\n" +
+	"\rinclude_lower\x18\x04 \x01(\bR\fincludeLower\x12%\n" +
+	"\x0einclude_digits\x18\x05 \x01(\bR\rincludeDigits\x12)\n" +
+	"\x10include_specials\x18\x06 \x01(\bR\x0fincludeSpecials\x12'\n" +
+	"\x0fexclude_similar\x18\a \x01(\bR\x0eexcludeSimilar\x12)\n" +
+	"\x10exclude_profiles\x18\b \x03(\tR\x0fexcludeProfiles\x12%\n" +
+	"\x0ecustom_charset\x18\t \x01(\tR\rcustomCharset\x12#\n" +
+	"\rexclude_chars\x18\n" +
+	" \x01(\tR\fexcludeChars\x12\x1f\n" +
+	"\venforce_all\x18\v \x01(\bR\n" +
+	"enforceAll\x12\x1e\n" +
+	"\n" +
+	"passphrase\x18\f \x01(\bR\n" +
+	"passphrase\x12\x1d\n" +
+	"\n" +
+	"word_count\x18\r \x01(\x05R\twordCount\x12$\n" +
+	"\rpronounceable\x18\x0e \x01(\bR\rpronounceable\x12\x10\n" +
+	"\x03pin\x18\x0f \x01(\bR\x03pin\x12\x1b\n" +
+	"\tpin_allow\x18\x10 \x03(\tR\bpinAllow\x12\x18\n" +
+	"\apattern\x18\x11 \x01(\tR\apattern\x12Y\n" +
+	"\x0fpattern_classes\x18\x12 \x03(\v20.pwdforge.v1.GenerateRequest.PatternClassesEntryR\x0epatternClasses\x12\x12\n" +
+	"\x04type\x18\x13 \x01(\tR\x04type\x12\x14\n" +
+	"\x05bytes\x18\x14 \x01(\x05R\x05bytes\x12\x1d\n" +
+	"\n" +
+	"key_prefix\x18\x15 \x01(\tR\tkeyPrefix\x12\x16\n" +
+	"\x06target\x18\x16 \x01(\tR\x06target\x12\x12\n" +
+	"\x04hash\x18\x17 \x01(\tR\x04hash\x12\x1b\n" +
+	"\thash_only\x18\x18 \x01(\bR\bhashOnly\x12C\n" +
+	"\acontext\x18\x19 \x03(\v2).pwdforge.v1.GenerateRequest.ContextEntryR\acontext\x12+\n" +
+	"\x06policy\x18\x1a \x01(\v2\x13.pwdforge.v1.PolicyR\x06policy\x1aA\n" +
+	"\x13PatternClassesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a:\n" +
+	"\fContextEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x93\x01\n" +
+	"\x0fGeneratedSecret\x12\x1a\n" +
+	"\bpassword\x18\x01 \x01(\tR\bpassword\x12\x1a\n" +
+	"\brendered\x18\x02 \x01(\tR\brendered\x12\x12\n" +
+	"\x04hash\x18\x03 \x01(\tR\x04hash\x12\x1a\n" +
+	"\bstrength\x18\x04 \x01(\tR\bstrength\x12\x18\n" +
+	"\aentropy\x18\x05 \x01(\x01R\aentropy\"N\n" +
+	"\x10GenerateResponse\x12:\n" +
+	"\tpasswords\x18\x01 \x03(\v2\x1c.pwdforge.v1.GeneratedSecretR\tpasswords\"\x88\x02\n" +
+	"\x14CheckStrengthRequest\x12\x1a\n" +
+	"\bpassword\x18\x01 \x01(\tR\bpassword\x12!\n" +
+	"\fbanned_words\x18\x02 \x03(\tR\vbannedWords\x12H\n" +
+	"\acontext\x18\x03 \x03(\v2..pwdforge.v1.CheckStrengthRequest.ContextEntryR\acontext\x12+\n" +
+	"\x06policy\x18\x04 \x01(\v2\x13.pwdforge.v1.PolicyR\x06policy\x1a:\n" +
+	"\fContextEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"P\n" +
+	"\n" +
+	"PolicyRule\x12\x12\n" +
+	"\x04rule\x18\x01 \x01(\tR\x04rule\x12\x16\n" +
+	"\x06passed\x18\x02 \x01(\bR\x06passed\x12\x16\n" +
+	"\x06detail\x18\x03 \x01(\tR\x06detail\"U\n" +
+	"\fPolicyReport\x12\x16\n" +
+	"\x06passed\x18\x01 \x01(\bR\x06passed\x12-\n" +
+	"\x05rules\x18\x02 \x03(\v2\x17.pwdforge.v1.PolicyRuleR\x05rules\"\xa2\x01\n" +
+	"\x15CheckStrengthResponse\x12\x1a\n" +
+	"\bstrength\x18\x01 \x01(\tR\bstrength\x12\x18\n" +
+	"\aentropy\x18\x02 \x01(\x01R\aentropy\x12 \n" +
+	"\vsuggestions\x18\x03 \x03(\tR\vsuggestions\x121\n" +
+	"\x06policy\x18\x04 \x01(\v2\x19.pwdforge.v1.PolicyReportR\x06policy\"\x81\x01\n" +
+	"\x13CheckStrengthResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x04R\x05index\x12>\n" +
+	"\bresponse\x18\x02 \x01(\v2\".pwdforge.v1.CheckStrengthResponseR\bresponse\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"Q\n" +
+	"\x11CheckPwnedRequest\x12\x1c\n" +
+	"\bpassword\x18\x01 \x01(\tH\x00R\bpassword\x12\x14\n" +
+	"\x04sha1\x18\x02 \x01(\tH\x00R\x04sha1B\b\n" +
+	"\x06secret\"@\n" +
+	"\x12CheckPwnedResponse\x12\x14\n" +
+	"\x05pwned\x18\x01 \x01(\bR\x05pwned\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"{\n" +
+	"\x10CheckPwnedResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x04R\x05index\x12;\n" +
+	"\bresponse\x18\x02 \x01(\v2\x1f.pwdforge.v1.CheckPwnedResponseR\bresponse\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error2\xaf\x03\n" +
+	"\bPwdForge\x12G\n" +
+	"\bGenerate\x12\x1c.pwdforge.v1.GenerateRequest\x1a\x1d.pwdforge.v1.GenerateResponse\x12V\n" +
+	"\rCheckStrength\x12!.pwdforge.v1.CheckStrengthRequest\x1a\".pwdforge.v1.CheckStrengthResponse\x12M\n" +
+	"\n" +
+	"CheckPwned\x12\x1e.pwdforge.v1.CheckPwnedRequest\x1a\x1f.pwdforge.v1.CheckPwnedResponse\x12]\n" +
+	"\x12CheckStrengthBatch\x12!.pwdforge.v1.CheckStrengthRequest\x1a .pwdforge.v1.CheckStrengthResult(\x010\x01\x12T\n" +
+	"\x0fCheckPwnedBatch\x12\x1e.pwdforge.v1.CheckPwnedRequest\x1a\x1d.pwdforge.v1.CheckPwnedResult(\x010\x01B$Z\"pwdforge/pkg/pwdforgepb;pwdforgepbb\x06proto3"
+
+var (
+	file_pwdforge_v1_pwdforge_proto_rawDescOnce sync.Once
+	file_pwdforge_v1_pwdforge_proto_rawDescData []byte
+)
+
+func file_pwdforge_v1_pwdforge_proto_rawDescGZIP() []byte {
+	file_pwdforge_v1_pwdforge_proto_rawDescOnce.Do(func() {
+		file_pwdforge_v1_pwdforge_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pwdforge_v1_pwdforge_proto_rawDesc), len(file_pwdforge_v1_pwdforge_proto_rawDesc)))
+	})
+	return file_pwdforge_v1_pwdforge_proto_rawDescData
+}
+
+var file_pwdforge_v1_pwdforge_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_pwdforge_v1_pwdforge_proto_goTypes = []any{
+	(*Policy)(nil),                // 0: pwdforge.v1.Policy
+	(*GenerateRequest)(nil),       // 1: pwdforge.v1.GenerateRequest
+	(*GeneratedSecret)(nil),       // 2: pwdforge.v1.GeneratedSecret
+	(*GenerateResponse)(nil),      // 3: pwdforge.v1.GenerateResponse
+	(*CheckStrengthRequest)(nil),  // 4: pwdforge.v1.CheckStrengthRequest
+	(*PolicyRule)(nil),            // 5: pwdforge.v1.PolicyRule
+	(*PolicyReport)(nil),          // 6: pwdforge.v1.PolicyReport
+	(*CheckStrengthResponse)(nil), // 7: pwdforge.v1.CheckStrengthResponse
+	(*CheckStrengthResult)(nil),   // 8: pwdforge.v1.CheckStrengthResult
+	(*CheckPwnedRequest)(nil),     // 9: pwdforge.v1.CheckPwnedRequest
+	(*CheckPwnedResponse)(nil),    // 10: pwdforge.v1.CheckPwnedResponse
+	(*CheckPwnedResult)(nil),      // 11: pwdforge.v1.CheckPwnedResult
+	nil,                           // 12: pwdforge.v1.GenerateRequest.PatternClassesEntry
+	nil,                           // 13: pwdforge.v1.GenerateRequest.ContextEntry
+	nil,                           // 14: pwdforge.v1.CheckStrengthRequest.ContextEntry
+}
+var file_pwdforge_v1_pwdforge_proto_depIdxs = []int32{
+	12, // 0: pwdforge.v1.GenerateRequest.pattern_classes:type_name -> pwdforge.v1.GenerateRequest.PatternClassesEntry
+	13, // 1: pwdforge.v1.GenerateRequest.context:type_name -> pwdforge.v1.GenerateRequest.ContextEntry
+	0,  // 2: pwdforge.v1.GenerateRequest.policy:type_name -> pwdforge.v1.Policy
+	2,  // 3: pwdforge.v1.GenerateResponse.passwords:type_name -> pwdforge.v1.GeneratedSecret
+	14, // 4: pwdforge.v1.CheckStrengthRequest.context:type_name -> pwdforge.v1.CheckStrengthRequest.ContextEntry
+	0,  // 5: pwdforge.v1.CheckStrengthRequest.policy:type_name -> pwdforge.v1.Policy
+	5,  // 6: pwdforge.v1.PolicyReport.rules:type_name -> pwdforge.v1.PolicyRule
+	6,  // 7: pwdforge.v1.CheckStrengthResponse.policy:type_name -> pwdforge.v1.PolicyReport
+	7,  // 8: pwdforge.v1.CheckStrengthResult.response:type_name -> pwdforge.v1.CheckStrengthResponse
+	10, // 9: pwdforge.v1.CheckPwnedResult.response:type_name -> pwdforge.v1.CheckPwnedResponse
+	1,  // 10: pwdforge.v1.PwdForge.Generate:input_type -> pwdforge.v1.GenerateRequest
+	4,  // 11: pwdforge.v1.PwdForge.CheckStrength:input_type -> pwdforge.v1.CheckStrengthRequest
+	9,  // 12: pwdforge.v1.PwdForge.CheckPwned:input_type -> pwdforge.v1.CheckPwnedRequest
+	4,  // 13: pwdforge.v1.PwdForge.CheckStrengthBatch:input_type -> pwdforge.v1.CheckStrengthRequest
+	9,  // 14: pwdforge.v1.PwdForge.CheckPwnedBatch:input_type -> pwdforge.v1.CheckPwnedRequest
+	3,  // 15: pwdforge.v1.PwdForge.Generate:output_type -> pwdforge.v1.GenerateResponse
+	7,  // 16: pwdforge.v1.PwdForge.CheckStrength:output_type -> pwdforge.v1.CheckStrengthResponse
+	10, // 17: pwdforge.v1.PwdForge.CheckPwned:output_type -> pwdforge.v1.CheckPwnedResponse
+	8,  // 18: pwdforge.v1.PwdForge.CheckStrengthBatch:output_type -> pwdforge.v1.CheckStrengthResult
+	11, // 19: pwdforge.v1.PwdForge.CheckPwnedBatch:output_type -> pwdforge.v1.CheckPwnedResult
+	15, // [15:20] is the sub-list for method output_type
+	10, // [10:15] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_pwdforge_v1_pwdforge_proto_init() }
+func file_pwdforge_v1_pwdforge_proto_init() {
+	if File_pwdforge_v1_pwdforge_proto != nil {
+		return
+	}
+	file_pwdforge_v1_pwdforge_proto_msgTypes[9].OneofWrappers = []any{
+		(*CheckPwnedRequest_Password)(nil),
+		(*CheckPwnedRequest_Sha1)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pwdforge_v1_pwdforge_proto_rawDesc), len(file_pwdforge_v1_pwdforge_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pwdforge_v1_pwdforge_proto_goTypes,
+		DependencyIndexes: file_pwdforge_v1_pwdforge_proto_depIdxs,
+		MessageInfos:      file_pwdforge_v1_pwdforge_proto_msgTypes,
+	}.Build()
+	File_pwdforge_v1_pwdforge_proto = out.File
+	file_pwdforge_v1_pwdforge_proto_goTypes = nil
+	file_pwdforge_v1_pwdforge_proto_depIdxs = nil
+}
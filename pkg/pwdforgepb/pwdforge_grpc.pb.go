@@ -0,0 +1,277 @@
+// gRPC interface of PwdForge. It mirrors the JSON HTTP API served by
+// "pwdforge serve": field names match the config file keys.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: pwdforge/v1/pwdforge.proto
+
+package pwdforgepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PwdForge_Generate_FullMethodName           = "/pwdforge.v1.PwdForge/Generate"
+	PwdForge_CheckStrength_FullMethodName      = "/pwdforge.v1.PwdForge/CheckStrength"
+	PwdForge_CheckPwned_FullMethodName         = "/pwdforge.v1.PwdForge/CheckPwned"
+	PwdForge_CheckStrengthBatch_FullMethodName = "/pwdforge.v1.PwdForge/CheckStrengthBatch"
+	PwdForge_CheckPwnedBatch_FullMethodName    = "/pwdforge.v1.PwdForge/CheckPwnedBatch"
+)
+
+// PwdForgeClient is the client API for PwdForge service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PwdForgeClient interface {
+	// Generate creates passwords, passphrases, PINs or tokens.
+	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error)
+	// CheckStrength rates a password and optionally checks it against a policy.
+	CheckStrength(ctx context.Context, in *CheckStrengthRequest, opts ...grpc.CallOption) (*CheckStrengthResponse, error)
+	// CheckPwned looks a password up in HaveIBeenPwned.
+	CheckPwned(ctx context.Context, in *CheckPwnedRequest, opts ...grpc.CallOption) (*CheckPwnedResponse, error)
+	// CheckStrengthBatch and CheckPwnedBatch answer each request on the stream
+	// with one result, in order. A failing item reports its error in the
+	// result instead of ending the stream.
+	CheckStrengthBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CheckStrengthRequest, CheckStrengthResult], error)
+	CheckPwnedBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CheckPwnedRequest, CheckPwnedResult], error)
+}
+
+type pwdForgeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPwdForgeClient(cc grpc.ClientConnInterface) PwdForgeClient {
+	return &pwdForgeClient{cc}
+}
+
+func (c *pwdForgeClient) Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateResponse)
+	err := c.cc.Invoke(ctx, PwdForge_Generate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pwdForgeClient) CheckStrength(ctx context.Context, in *CheckStrengthRequest, opts ...grpc.CallOption) (*CheckStrengthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckStrengthResponse)
+	err := c.cc.Invoke(ctx, PwdForge_CheckStrength_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pwdForgeClient) CheckPwned(ctx context.Context, in *CheckPwnedRequest, opts ...grpc.CallOption) (*CheckPwnedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckPwnedResponse)
+	err := c.cc.Invoke(ctx, PwdForge_CheckPwned_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pwdForgeClient) CheckStrengthBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CheckStrengthRequest, CheckStrengthResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PwdForge_ServiceDesc.Streams[0], PwdForge_CheckStrengthBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CheckStrengthRequest, CheckStrengthResult]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PwdForge_CheckStrengthBatchClient = grpc.BidiStreamingClient[CheckStrengthRequest, CheckStrengthResult]
+
+func (c *pwdForgeClient) CheckPwnedBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CheckPwnedRequest, CheckPwnedResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PwdForge_ServiceDesc.Streams[1], PwdForge_CheckPwnedBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CheckPwnedRequest, CheckPwnedResult]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PwdForge_CheckPwnedBatchClient = grpc.BidiStreamingClient[CheckPwnedRequest, CheckPwnedResult]
+
+// PwdForgeServer is the server API for PwdForge service.
+// All implementations must embed UnimplementedPwdForgeServer
+// for forward compatibility.
+type PwdForgeServer interface {
+	// Generate creates passwords, passphrases, PINs or tokens.
+	Generate(context.Context, *GenerateRequest) (*GenerateResponse, error)
+	// CheckStrength rates a password and optionally checks it against a policy.
+	CheckStrength(context.Context, *CheckStrengthRequest) (*CheckStrengthResponse, error)
+	// CheckPwned looks a password up in HaveIBeenPwned.
+	CheckPwned(context.Context, *CheckPwnedRequest) (*CheckPwnedResponse, error)
+	// CheckStrengthBatch and CheckPwnedBatch answer each request on the stream
+	// with one result, in order. A failing item reports its error in the
+	// result instead of ending the stream.
+	CheckStrengthBatch(grpc.BidiStreamingServer[CheckStrengthRequest, CheckStrengthResult]) error
+	CheckPwnedBatch(grpc.BidiStreamingServer[CheckPwnedRequest, CheckPwnedResult]) error
+	mustEmbedUnimplementedPwdForgeServer()
+}
+
+// UnimplementedPwdForgeServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPwdForgeServer struct{}
+
+func (UnimplementedPwdForgeServer) Generate(context.Context, *GenerateRequest) (*GenerateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Generate not implemented")
+}
+func (UnimplementedPwdForgeServer) CheckStrength(context.Context, *CheckStrengthRequest) (*CheckStrengthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckStrength not implemented")
+}
+func (UnimplementedPwdForgeServer) CheckPwned(context.Context, *CheckPwnedRequest) (*CheckPwnedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckPwned not implemented")
+}
+func (UnimplementedPwdForgeServer) CheckStrengthBatch(grpc.BidiStreamingServer[CheckStrengthRequest, CheckStrengthResult]) error {
+	return status.Errorf(codes.Unimplemented, "method CheckStrengthBatch not implemented")
+}
+func (UnimplementedPwdForgeServer) CheckPwnedBatch(grpc.BidiStreamingServer[CheckPwnedRequest, CheckPwnedResult]) error {
+	return status.Errorf(codes.Unimplemented, "method CheckPwnedBatch not implemented")
+}
+func (UnimplementedPwdForgeServer) mustEmbedUnimplementedPwdForgeServer() {}
+func (UnimplementedPwdForgeServer) testEmbeddedByValue()                  {}
+
+// UnsafePwdForgeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PwdForgeServer will
+// result in compilation errors.
+type UnsafePwdForgeServer interface {
+	mustEmbedUnimplementedPwdForgeServer()
+}
+
+func RegisterPwdForgeServer(s grpc.ServiceRegistrar, srv PwdForgeServer) {
+	// If the following call pancis, it indicates UnimplementedPwdForgeServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PwdForge_ServiceDesc, srv)
+}
+
+func _PwdForge_Generate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PwdForgeServer).Generate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PwdForge_Generate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PwdForgeServer).Generate(ctx, req.(*GenerateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PwdForge_CheckStrength_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckStrengthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PwdForgeServer).CheckStrength(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PwdForge_CheckStrength_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PwdForgeServer).CheckStrength(ctx, req.(*CheckStrengthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PwdForge_CheckPwned_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckPwnedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PwdForgeServer).CheckPwned(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PwdForge_CheckPwned_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PwdForgeServer).CheckPwned(ctx, req.(*CheckPwnedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PwdForge_CheckStrengthBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PwdForgeServer).CheckStrengthBatch(&grpc.GenericServerStream[CheckStrengthRequest, CheckStrengthResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PwdForge_CheckStrengthBatchServer = grpc.BidiStreamingServer[CheckStrengthRequest, CheckStrengthResult]
+
+func _PwdForge_CheckPwnedBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PwdForgeServer).CheckPwnedBatch(&grpc.GenericServerStream[CheckPwnedRequest, CheckPwnedResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PwdForge_CheckPwnedBatchServer = grpc.BidiStreamingServer[CheckPwnedRequest, CheckPwnedResult]
+
+// PwdForge_ServiceDesc is the grpc.ServiceDesc for PwdForge service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PwdForge_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pwdforge.v1.PwdForge",
+	HandlerType: (*PwdForgeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Generate",
+			Handler:    _PwdForge_Generate_Handler,
+		},
+		{
+			MethodName: "CheckStrength",
+			Handler:    _PwdForge_CheckStrength_Handler,
+		},
+		{
+			MethodName: "CheckPwned",
+			Handler:    _PwdForge_CheckPwned_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CheckStrengthBatch",
+			Handler:       _PwdForge_CheckStrengthBatch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "CheckPwnedBatch",
+			Handler:       _PwdForge_CheckPwnedBatch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pwdforge/v1/pwdforge.proto",
+}